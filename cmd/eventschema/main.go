@@ -0,0 +1,30 @@
+// Command eventschema writes the JSON Schema for WebSocket events
+// Run it through `go generate ./internal/domain` after changing an event struct
+package main
+
+import (
+	"encoding/json"
+	ws "fileprintapp/internal/websocket"
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(ws.EventSchema(), "", "  ")
+	if err != nil {
+		log.Fatal("Failed to marshal schema:", err)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatal("Failed to write schema:", err)
+	}
+}
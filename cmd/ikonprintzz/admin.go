@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fileprintapp/internal/app"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// adminView is how admins are printed; password hashes are never shown
+type adminView struct {
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+	Disabled  bool      `json:"disabled"`
+}
+
+func newAdminView(admin *domain.Admin) adminView {
+	return adminView{Username: admin.Username, CreatedAt: admin.CreatedAt, Disabled: admin.Disabled}
+}
+
+// runAdmins lists admin accounts
+func runAdmins(cfg *config.Config, args []string) error {
+	flags := newFlags("admins")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	admins, err := services.Auth.ListAdmins(context.Background())
+	if err != nil {
+		return err
+	}
+
+	views := make([]adminView, 0, len(admins))
+	for _, admin := range admins {
+		views = append(views, newAdminView(admin))
+	}
+	return printResult(views, func(w io.Writer) {
+		fmt.Fprintln(w, "USERNAME\tCREATED\tSTATUS")
+		for _, v := range views {
+			status := "enabled"
+			if v.Disabled {
+				status = "disabled"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", v.Username, v.CreatedAt.Format(time.RFC3339), status)
+		}
+	})
+}
+
+// runCreateAdmin adds an admin account
+func runCreateAdmin(cfg *config.Config, args []string) error {
+	return changeAdmin(cfg, "create-admin", args, true, func(ctx context.Context, s *app.Services, username, password string) error {
+		return s.Auth.CreateAdmin(ctx, username, password)
+	})
+}
+
+// runResetPassword sets a new password for an existing admin
+func runResetPassword(cfg *config.Config, args []string) error {
+	return changeAdmin(cfg, "reset-password", args, true, func(ctx context.Context, s *app.Services, username, password string) error {
+		return s.Auth.ResetPassword(ctx, username, password)
+	})
+}
+
+// runDisableAdmin blocks an admin's logins and rejects the tokens it holds
+func runDisableAdmin(cfg *config.Config, args []string) error {
+	return changeAdmin(cfg, "disable-admin", args, false, func(ctx context.Context, s *app.Services, username, _ string) error {
+		return s.Auth.SetDisabled(ctx, username, true)
+	})
+}
+
+// runEnableAdmin re-enables a disabled admin
+func runEnableAdmin(cfg *config.Config, args []string) error {
+	return changeAdmin(cfg, "enable-admin", args, false, func(ctx context.Context, s *app.Services, username, _ string) error {
+		return s.Auth.SetDisabled(ctx, username, false)
+	})
+}
+
+// changeAdmin runs a command that takes one username, optionally reading a
+// password first, and prints the admin afterwards
+func changeAdmin(cfg *config.Config, name string, args []string, withPassword bool,
+	change func(ctx context.Context, s *app.Services, username, password string) error) error {
+	flags := newFlags(name)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s [-json] <username>", name)
+	}
+	username := flags.Arg(0)
+
+	var password string
+	if withPassword {
+		var err error
+		if password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx := context.Background()
+	if err := change(ctx, services, username, password); err != nil {
+		return err
+	}
+
+	admin, err := backend.Admins.GetAdminByUsername(ctx, strings.TrimSpace(username))
+	if err != nil {
+		return err
+	}
+	view := newAdminView(admin)
+	return printResult(view, func(w io.Writer) {
+		status := "enabled"
+		if view.Disabled {
+			status = "disabled"
+		}
+		fmt.Fprintf(w, "✅ %s: admin '%s' is %s\n", name, view.Username, status)
+	})
+}
+
+// readPassword prompts for a password (twice, without echo) on a terminal,
+// or reads the first line of stdin when it is piped
+// Passwords are never taken from arguments, which end up in shell history
+func readPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(os.Stderr, "Repeat password: ")
+	again, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(password) != string(again) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(password), nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"io"
+	"time"
+)
+
+// runFolders lists folders, newest first
+func runFolders(cfg *config.Config, args []string) error {
+	flags := newFlags("folders")
+	search := flags.String("q", "", "only folders whose name contains this text")
+	limit := flags.Int("limit", 0, "maximum number of folders (0 for all)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx := context.Background()
+	folders := make([]*domain.Folder, 0)
+	query := domain.FolderQuery{Search: *search, Limit: pageSize(*limit)}
+	for {
+		page, err := services.Folders.ListFolders(ctx, query)
+		if err != nil {
+			return err
+		}
+		folders = append(folders, page.Folders...)
+		if page.NextCursor == "" || (*limit > 0 && len(folders) >= *limit) {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if *limit > 0 && len(folders) > *limit {
+		folders = folders[:*limit]
+	}
+
+	return printResult(folders, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tCREATED\tFILES\tBYTES\tPAGES")
+		for _, f := range folders {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\n", f.ID, f.Name, f.CreatedAt.Format(time.RFC3339), f.FileCount, f.TotalBytes, f.TotalPages)
+		}
+	})
+}
+
+// runFiles lists files, newest first
+func runFiles(cfg *config.Config, args []string) error {
+	flags := newFlags("files")
+	folderID := flags.String("folder", "", "only files in this folder")
+	fileType := flags.String("type", "", "only files with this extension (e.g., pdf)")
+	search := flags.String("q", "", "only files whose file or folder name contains this text")
+	limit := flags.Int("limit", 0, "maximum number of files (0 for all)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx := context.Background()
+	files := make([]*domain.UploadedFile, 0)
+	query := domain.FileQuery{FolderID: *folderID, FileType: *fileType, Search: *search, Limit: pageSize(*limit)}
+	for {
+		page, err := services.Files.ListFiles(ctx, query)
+		if err != nil {
+			return err
+		}
+		files = append(files, page.Files...)
+		if page.NextCursor == "" || (*limit > 0 && len(files) >= *limit) {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if *limit > 0 && len(files) > *limit {
+		files = files[:*limit]
+	}
+
+	return printResult(files, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tFOLDER\tNAME\tUPLOADED\tBYTES\tPAGES")
+		for _, f := range files {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\n", f.ID, f.FolderName, f.FileName, f.UploadedAt.Format(time.RFC3339), f.FileSize, f.PageCount)
+		}
+	})
+}
+
+// runDeleteFolder deletes an order: a folder with all of its files and uploads
+// Open dashboards are notified through the event bus (see notifier)
+func runDeleteFolder(cfg *config.Config, args []string) error {
+	flags := newFlags("delete-folder")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: delete-folder [-json] <id>")
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	folder, err := services.Folders.DeleteFolder(context.Background(), flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	newNotifier(cfg, backend).publish(folder.ID, domain.NewFolderDeletedEvent(folder))
+	return printResult(folder, func(w io.Writer) {
+		fmt.Fprintf(w, "✅ Deleted folder %s %q (%d files)\n", folder.ID, folder.Name, folder.FileCount)
+	})
+}
+
+// runDeleteFile deletes one file and its upload
+// Open dashboards are notified through the event bus (see notifier)
+func runDeleteFile(cfg *config.Config, args []string) error {
+	flags := newFlags("delete-file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: delete-file [-json] <id>")
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	file, err := services.Files.DeleteFile(context.Background(), flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	newNotifier(cfg, backend).publish(file.FolderID, domain.NewFileDeletedEvent(file))
+	return printResult(file, func(w io.Writer) {
+		fmt.Fprintf(w, "✅ Deleted file %s %q from %q\n", file.ID, file.FileName, file.FolderName)
+	})
+}
+
+// runVerify reports file records without uploads and uploads without records
+// It fails when either is found, so it can run from cron or a health check
+func runVerify(cfg *config.Config, args []string) error {
+	flags := newFlags("verify")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	report, err := services.Files.VerifyStorage(context.Background())
+	if err != nil {
+		return err
+	}
+
+	err = printResult(report, func(w io.Writer) {
+		fmt.Fprintf(w, "Checked %d file record(s) and %d upload(s)\n", report.Files, report.Blobs)
+		for _, file := range report.MissingBlobs {
+			fmt.Fprintf(w, "missing upload\t%s\t%s\t%s\n", file.ID, file.FolderName, file.FilePath)
+		}
+		for _, path := range report.OrphanBlobs {
+			fmt.Fprintf(w, "orphan upload\t%s\n", path)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if !report.OK() {
+		// The report has been printed; a bare exit status avoids repeating it
+		exitStatus = 1
+	}
+	return nil
+}
+
+// pageSize picks the page size for fetching up to limit items (0 for all)
+func pageSize(limit int) int {
+	if limit > 0 && limit < domain.MaxPageSize {
+		return limit
+	}
+	return domain.MaxPageSize
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fileprintapp/internal/app"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/domain"
+	ws "fileprintapp/internal/websocket"
+	"log/slog"
+)
+
+// notifier tells running servers about changes made from the command line,
+// so open dashboards see them as if they had been made over HTTP
+// That takes the Postgres event bus; with EVENT_BUS=local each server only
+// knows about its own changes and dashboards see these when reloaded.
+type notifier struct {
+	bus ws.Bus // nil without EVENT_BUS=postgres
+}
+
+// newNotifier creates a notifier publishing through the configured event bus
+func newNotifier(cfg *config.Config, backend *app.Backend) *notifier {
+	if cfg.EventBus != "postgres" || backend.DB == nil {
+		slog.Info("EVENT_BUS is not postgres; running dashboards won't see this change until reloaded")
+		return &notifier{}
+	}
+	return &notifier{bus: ws.NewPostgresBus(backend.DB, app.PostgresConfig(cfg).DSN())}
+}
+
+// publish sends an event about a folder, as the HTTP handlers do
+// A failure is only logged: the change itself has been made.
+func (n *notifier) publish(folderID string, e domain.Event) {
+	if n.bus == nil {
+		return
+	}
+	ctx := context.Background()
+	msg, err := ws.NewBusMessage(ctx, ws.FolderTopic(folderID), e)
+	if err == nil {
+		err = n.bus.Publish(ctx, msg)
+	}
+	if err != nil {
+		slog.Warn("notifying running servers failed", "type", e.EventType(), "error", err)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// runExport writes a zip archive holding manifest.json (every folder with its
+// file records) and each upload, stored as <folder id>/<stored file name>
+func runExport(cfg *config.Config, args []string) error {
+	flags := newFlags("export")
+	output := flags.String("o", "ikonprintzz-export-"+time.Now().Format("20060102-150405")+".zip", "archive to write")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx := context.Background()
+
+	var folders []*domain.FolderWithFiles
+	query := domain.FolderQuery{Sort: domain.Sort{Field: domain.SortByDate, Ascending: true}, Limit: domain.MaxPageSize}
+	for {
+		page, err := services.Folders.ListFolders(ctx, query)
+		if err != nil {
+			return err
+		}
+		withFiles, err := services.Folders.WithFiles(ctx, page.Folders)
+		if err != nil {
+			return err
+		}
+		folders = append(folders, withFiles...)
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	archive := zip.NewWriter(out)
+	manifest, err := archive.CreateHeader(&zip.FileHeader{Name: "manifest.json", Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(manifest)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(folders); err != nil {
+		return err
+	}
+
+	files, missing := 0, 0
+	for _, folder := range folders {
+		for _, file := range folder.Files {
+			name := path.Join(folder.ID, filepath.Base(file.FilePath))
+			if err := addFile(archive, name, file); err != nil {
+				if !os.IsNotExist(err) {
+					return err
+				}
+				// Keep going: the manifest still records the file
+				slog.Warn("upload is missing from storage", "path", file.FilePath)
+				missing++
+				continue
+			}
+			files++
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	result := struct {
+		Output  string `json:"output"`
+		Folders int    `json:"folders"`
+		Uploads int    `json:"uploads"`
+		Missing int    `json:"missing"` // Recorded in the manifest, but not found in storage
+	}{*output, len(folders), files, missing}
+	err = printResult(result, func(w io.Writer) {
+		fmt.Fprintf(w, "✅ Exported %d folder(s) and %d upload(s) to %s\n", result.Folders, result.Uploads, result.Output)
+		if missing > 0 {
+			fmt.Fprintf(w, "⚠️  %d upload(s) were missing from storage\n", missing)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if missing > 0 {
+		exitStatus = 1
+	}
+	return nil
+}
+
+// addFile copies one upload into the archive
+func addFile(archive *zip.Writer, name string, file *domain.UploadedFile) error {
+	blob, err := os.Open(file.FilePath)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: file.UploadedAt}
+	w, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, blob)
+	return err
+}
@@ -0,0 +1,152 @@
+// Command ikonprintzz runs the File Print Service and its maintenance tasks
+//
+// Usage:
+//
+//	ikonprintzz serve                           start the HTTP server
+//	ikonprintzz migrate up | down [n] | status  manage PostgreSQL schema migrations
+//	ikonprintzz admins                          list admin accounts
+//	ikonprintzz create-admin <username>         add an admin (password read from the terminal or stdin)
+//	ikonprintzz disable-admin <username>        block an admin's logins and tokens
+//	ikonprintzz enable-admin <username>         re-enable a disabled admin
+//	ikonprintzz reset-password <username>       set an admin's password
+//	ikonprintzz folders [-q text] [-limit n]    list folders, newest first
+//	ikonprintzz files [-folder id] [-type ext]  list files, newest first
+//	ikonprintzz delete-folder <id>              delete an order: a folder and its uploads
+//	ikonprintzz delete-file <id>                delete one upload
+//	ikonprintzz verify                          check file records against uploads on disk
+//	ikonprintzz purge -days <n> [-dry-run]      delete folders created more than n days ago
+//	ikonprintzz export [-o <file.zip>]          archive every folder, file record and upload
+//
+// Every command reads the same configuration (environment variables, .env or
+// the YAML/TOML file named by CONFIG_FILE), refuses to run if any setting is
+// invalid, and talks to the backend selected by DB_DRIVER. Maintenance
+// commands accept -json (before any other argument) to print machine-readable
+// JSON on stdout; failures are then printed as {"error": "..."}. Log lines go
+// to stderr. Deletions reach running dashboards only with EVENT_BUS=postgres;
+// otherwise dashboards show them when reloaded.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fileprintapp/internal/app"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/logging"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+)
+
+// notifyNote is appended to the help of commands whose changes are only
+// pushed to running dashboards through the Postgres event bus
+const notifyNote = "; dashboards are notified only with EVENT_BUS=postgres"
+
+// command is one subcommand of the binary
+type command struct {
+	name  string
+	usage string
+	run   func(cfg *config.Config, args []string) error
+}
+
+var commands = []command{
+	{"serve", "start the HTTP server", runServe},
+	{"migrate", "up | down [n] | status: manage PostgreSQL schema migrations", runMigrate},
+	{"admins", "list admin accounts", runAdmins},
+	{"create-admin", "<username>: add an admin account", runCreateAdmin},
+	{"disable-admin", "<username>: block an admin's logins and tokens", runDisableAdmin},
+	{"enable-admin", "<username>: re-enable a disabled admin", runEnableAdmin},
+	{"reset-password", "<username>: set a new password for an admin", runResetPassword},
+	{"folders", "[-q text] [-limit n]: list folders, newest first", runFolders},
+	{"files", "[-folder id] [-type ext] [-q text] [-limit n]: list files, newest first", runFiles},
+	{"delete-folder", "<id>: delete an order (a folder and all of its uploads)" + notifyNote, runDeleteFolder},
+	{"delete-file", "<id>: delete one upload" + notifyNote, runDeleteFile},
+	{"verify", "check that every file record has its upload on disk and vice versa", runVerify},
+	{"purge", "-days <n> [-dry-run]: delete folders created more than n days ago" + notifyNote, runPurge},
+	{"export", "[-o <file.zip>]: archive every folder, file record and upload", runExport},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+			os.Exit(1)
+		}
+		level, _ := logging.ParseLevel(cfg.LogLevel) // Checked by LoadConfig
+		logging.Setup(os.Stderr, cfg.Environment, level)
+
+		if err := cmd.run(cfg, os.Args[2:]); err != nil {
+			if jsonOutput {
+				json.NewEncoder(os.Stdout).Encode(map[string]string{"error": err.Error()})
+				os.Exit(1)
+			}
+			slog.Error(cmd.name+" failed", "error", err)
+			os.Exit(1)
+		}
+		os.Exit(exitStatus)
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ikonprintzz <command> [-json] [arguments]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// exitStatus lets a command that completed (and printed its result) still
+// report failure, e.g. verify finding inconsistencies
+var exitStatus int
+
+// jsonOutput is set by the -json flag every maintenance command accepts
+var jsonOutput bool
+
+// newFlags creates a command's flag set, including the shared -json flag
+func newFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.BoolVar(&jsonOutput, "json", false, "print machine-readable JSON on stdout")
+	return flags
+}
+
+// printResult prints v as indented JSON with -json, otherwise as text
+// Text is written through a tabwriter, so columns can be separated by tabs
+func printResult(v interface{}, text func(w io.Writer)) error {
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	text(w)
+	return w.Flush()
+}
+
+// openBackend opens the configured backend for a maintenance command
+// The in-memory backend starts empty on every run, so it has nothing to maintain
+func openBackend(cfg *config.Config) (*app.Backend, *app.Services, error) {
+	if cfg.UsesMemory() {
+		return nil, nil, errors.New("DB_DRIVER=memory keeps no data between runs; use postgres or sqlite")
+	}
+	backend, err := app.Open(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return backend, app.NewServices(cfg, backend), nil
+}
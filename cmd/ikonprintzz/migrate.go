@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/app"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/database"
+	"fileprintapp/migrations"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// runMigrate applies, reverts and reports PostgreSQL schema migrations
+// SQLite applies its schema whenever it is opened, so only "up" is meaningful there
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) < 1 {
+		return errors.New("usage: migrate up | down [n] | status")
+	}
+
+	db, err := app.Connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	if cfg.UsesSQLite() {
+		if args[0] != "up" {
+			return fmt.Errorf("migrate %s is only supported for PostgreSQL", args[0])
+		}
+		slog.Info("SQLite schema is up to date")
+		return nil
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return database.Migrate(ctx, db, migrations.FS)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		return database.MigrateDown(ctx, db, migrations.FS, steps)
+
+	case "status":
+		statuses, err := database.GetMigrationStatus(ctx, db, migrations.FS)
+		if err != nil {
+			return fmt.Errorf("failed to read migration status: %w", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				state += " (MODIFIED)"
+			}
+			fmt.Printf("%03d_%-30s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
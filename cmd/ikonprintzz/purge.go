@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"io"
+	"time"
+)
+
+// runPurge deletes folders (with their files and uploads) created before a cutoff
+// Open dashboards are notified through the event bus (see notifier)
+func runPurge(cfg *config.Config, args []string) error {
+	flags := newFlags("purge")
+	days := flags.Int("days", 0, "delete folders created more than this many days ago")
+	dryRun := flags.Bool("dry-run", false, "list the folders that would be deleted without deleting them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *days < 1 {
+		return errors.New("-days must be at least 1")
+	}
+
+	backend, services, err := openBackend(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx := context.Background()
+	cutoff := time.Now().AddDate(0, 0, -*days)
+
+	// Collect every match first so deletions can't disturb the paging
+	var folders []*domain.Folder
+	query := domain.FolderQuery{To: cutoff, Sort: domain.Sort{Field: domain.SortByDate, Ascending: true}, Limit: domain.MaxPageSize}
+	for {
+		page, err := services.Folders.ListFolders(ctx, query)
+		if err != nil {
+			return err
+		}
+		folders = append(folders, page.Folders...)
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	if !*dryRun {
+		events := newNotifier(cfg, backend)
+		for _, folder := range folders {
+			deleted, err := services.Folders.DeleteFolder(ctx, folder.ID)
+			if err != nil {
+				return err
+			}
+			events.publish(deleted.ID, domain.NewFolderDeletedEvent(deleted))
+		}
+	}
+
+	result := struct {
+		Cutoff  time.Time        `json:"cutoff"`
+		DryRun  bool             `json:"dry_run"`
+		Folders []*domain.Folder `json:"folders"` // Deleted, or to be deleted with -dry-run
+	}{cutoff, *dryRun, folders}
+	if result.Folders == nil {
+		result.Folders = make([]*domain.Folder, 0)
+	}
+	return printResult(result, func(w io.Writer) {
+		verb := "Deleted"
+		if *dryRun {
+			verb = "Would delete"
+		}
+		for _, f := range folders {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d files\t%s\n", verb, f.ID, f.Name, f.FileCount, f.CreatedAt.Format(time.RFC3339))
+		}
+		fmt.Fprintf(w, "✅ %d folder(s) created before %s\n", len(folders), cutoff.Format(time.RFC3339))
+	})
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fileprintapp/internal/app"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/tracing"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// runServe starts the HTTP server and blocks until it stops
+func runServe(cfg *config.Config, args []string) error {
+	slog.Info("configuration loaded", "environment", cfg.Environment, "db_driver", cfg.DBDriver, "storage_path", cfg.StoragePath)
+
+	// Ensure the uploads directory exists for file storage
+	if err := os.MkdirAll(cfg.StoragePath, 0755); err != nil {
+		return fmt.Errorf("failed to create uploads directory: %w", err)
+	}
+
+	// Export traces when OTEL_EXPORTER_OTLP_ENDPOINT is set; spans still
+	// buffered at exit are flushed after everything else has stopped
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.OTLPEndpoint, app.Version)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("failed to flush traces", "error", err)
+		}
+	}()
+	if cfg.OTLPEndpoint != "" {
+		slog.Info("exporting traces", "endpoint", cfg.OTLPEndpoint)
+	}
+
+	// Connect, migrate and build repositories for DB_DRIVER
+	backend, err := app.Open(cfg)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	services := app.NewServices(cfg, backend)
+
+	// Create the configured admin on first start; later password changes
+	// made with reset-password are kept
+	if err := services.Auth.EnsureAdmin(context.Background(), cfg.AdminUsername, cfg.AdminPassword); err != nil {
+		return fmt.Errorf("failed to initialize admin: %w", err)
+	}
+
+	// WebSocket hub runs in the background until shutdown
+	hub := app.NewHub(cfg, backend)
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	go hub.Run(hubCtx)
+
+	router := app.NewRouter(services, hub, app.NewMetricsHandler(cfg, backend, hub), app.NewHealthService(cfg, backend, services, hub), app.NewRateLimits(cfg))
+
+	addr := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+
+	server := &http.Server{Addr: addr, Handler: router}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	// The admin login page is not linked from the upload page
+	slog.Info("server started",
+		"upload_page", fmt.Sprintf("http://%s", addr),
+		"admin_login", fmt.Sprintf("http://%s/admin", addr),
+		"admin_username", cfg.AdminUsername,
+	)
+
+	// Run until an interrupt signal (Ctrl+C, SIGTERM from hosting platform)
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	select {
+	case err := <-serveErr:
+		stopHub()
+		return err
+	case <-signals.Done():
+		stopSignals() // A second Ctrl+C kills the process without waiting
+	}
+
+	slog.Info("shutdown signal received, draining", "timeout", cfg.ShutdownTimeout.String())
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Refuse new uploads, then stop listening and wait for in-flight requests
+	services.Files.StopUploads()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("requests still running at the deadline, closing connections", "error", err)
+		server.Close()
+	}
+	// An upload whose client went away keeps running until it has cleaned up
+	if err := services.Files.WaitForUploads(ctx); err != nil {
+		slog.Warn("uploads still running at the deadline", "error", err)
+	}
+
+	// Send close frames to WebSocket clients, which Shutdown doesn't track
+	stopHub()
+	<-hub.Done()
+	if err := hub.WaitForClients(ctx); err != nil {
+		slog.Warn("websocket clients still closing at the deadline", "error", err)
+	}
+
+	// The deferred backend.Close closes the database connection last
+	slog.Info("server stopped")
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package app wires configuration, storage, services and routes together
+// Every command of the ikonprintzz binary builds on it, so the server and
+// the admin commands always see the same backend and the same route table.
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/database"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/repository/memory"
+	"fileprintapp/internal/repository/postgres"
+	"fileprintapp/internal/repository/sqlite"
+	"fileprintapp/internal/usecase"
+	"fmt"
+	"log/slog"
+)
+
+// Backend is the storage selected by DB_DRIVER
+type Backend struct {
+	DB         *sql.DB // nil for the in-memory backend
+	Files      domain.FileRepository
+	Folders    domain.FolderRepository
+	Admins     domain.AdminRepository
+	Search     domain.SearchRepository
+	Settings   domain.SettingsRepository
+	UnitOfWork domain.UnitOfWork // Runs multi-step writes in one transaction
+}
+
+// PostgresConfig builds the PostgreSQL connection settings from cfg
+func PostgresConfig(cfg *config.Config) database.Config {
+	return database.Config{
+		Host:     cfg.DBHost,
+		Port:     cfg.DBPort,
+		User:     cfg.DBUser,
+		Password: cfg.DBPassword,
+		DBName:   cfg.DBName,
+		SSLMode:  cfg.DBSSLMode,
+	}
+}
+
+// Connect opens the configured database without touching its schema beyond
+// what opening requires (SQLite applies its schema on open)
+func Connect(cfg *config.Config) (*sql.DB, error) {
+	switch cfg.DBDriver {
+	case "postgres":
+		slog.Info("connecting to PostgreSQL", "host", cfg.DBHost, "database", cfg.DBName)
+		return database.Connect(PostgresConfig(cfg))
+	case "sqlite":
+		slog.Info("opening SQLite database", "path", cfg.SQLitePath)
+		return sqlite.Open(cfg.SQLitePath)
+	case "memory":
+		return nil, errors.New("the memory driver has no database")
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q", cfg.DBDriver)
+	}
+}
+
+// Open connects to the configured backend, applies pending PostgreSQL
+// migrations and builds its repositories
+func Open(cfg *config.Config) (*Backend, error) {
+	if cfg.UsesMemory() {
+		slog.Warn("using in-memory storage; data is lost on restart")
+		passwordHash, err := usecase.HashPassword(cfg.AdminPassword)
+		if err != nil {
+			return nil, err
+		}
+		files := memory.NewFileRepository()
+		folders := memory.NewFolderRepository(files)
+		return &Backend{
+			Files:      files,
+			Folders:    folders,
+			Admins:     memory.NewAdminRepository(cfg.AdminUsername, passwordHash),
+			Search:     memory.NewSearchRepository(files, folders),
+			Settings:   memory.NewSettingsRepository(),
+			UnitOfWork: memory.NewUnitOfWork(files, folders),
+		}, nil
+	}
+
+	db, err := Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.UsesSQLite() {
+		return &Backend{
+			DB:         db,
+			Files:      sqlite.NewFileRepository(db),
+			Folders:    sqlite.NewFolderRepository(db),
+			Admins:     sqlite.NewAdminRepository(db),
+			Search:     sqlite.NewSearchRepository(db),
+			Settings:   sqlite.NewSettingsRepository(db),
+			UnitOfWork: sqlite.NewUnitOfWork(db),
+		}, nil
+	}
+
+	// Safe on every start: applied migrations are recorded
+	if err := database.RunMigrations(db); err != nil {
+		database.Close(db)
+		return nil, err
+	}
+	return &Backend{
+		DB:         db,
+		Files:      postgres.NewFileRepository(db),
+		Folders:    postgres.NewFolderRepository(db),
+		Admins:     postgres.NewAdminRepository(db),
+		Search:     postgres.NewSearchRepository(db),
+		Settings:   postgres.NewSettingsRepository(db),
+		UnitOfWork: postgres.NewUnitOfWork(db),
+	}, nil
+}
+
+// Close closes the database connection, if any
+func (b *Backend) Close() {
+	database.Close(b.DB)
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/database"
+	"fileprintapp/internal/health"
+	ws "fileprintapp/internal/websocket"
+	"fileprintapp/migrations"
+)
+
+// Version is the release the binary was built as, set with
+// -ldflags "-X fileprintapp/internal/app.Version=v1.2.3"
+var Version = "dev"
+
+// NewHealthService builds the readiness checks and diagnostics for the server
+// Ready means the database answers, uploads can be written and the disk has
+// MIN_FREE_DISK free, the WebSocket hub runs and shutdown hasn't started
+func NewHealthService(cfg *config.Config, b *Backend, s *Services, hub *ws.Hub) *health.Service {
+	checks := []health.Check{
+		health.DatabaseCheck(b.DB),
+		health.StorageCheck(cfg.StoragePath),
+		health.DiskCheck(cfg.StoragePath, cfg.MinFreeDisk),
+		health.FuncCheck("websocket", "WebSocket hub is not running", hub.Running),
+		health.FuncCheck("uploads", "server is shutting down", s.Files.AcceptingUploads),
+	}
+	return health.NewService(Version, checks, cfg.Summary(), cfg.StoragePath, migrationLevel(cfg, b), hub)
+}
+
+// migrationLevel reports the schema version of the configured backend
+func migrationLevel(cfg *config.Config, b *Backend) func(ctx context.Context) (*health.MigrationLevel, error) {
+	return func(ctx context.Context) (*health.MigrationLevel, error) {
+		level := &health.MigrationLevel{Driver: cfg.DBDriver}
+		switch {
+		case cfg.UsesMemory():
+			level.Note = "in-memory storage has no schema"
+			return level, nil
+		case cfg.UsesSQLite():
+			level.Note = "SQLite applies its schema when the database is opened"
+			return level, nil
+		}
+
+		statuses, err := database.GetMigrationStatus(ctx, b.DB, migrations.FS)
+		if err != nil {
+			return nil, err
+		}
+		for _, status := range statuses {
+			level.Latest = status.Version
+			if !status.Applied {
+				level.Pending++
+				continue
+			}
+			level.Current = status.Version
+			level.Modified = level.Modified || status.Modified
+		}
+		return level, nil
+	}
+}
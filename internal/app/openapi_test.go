@@ -0,0 +1,103 @@
+package app_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// pages are the routes serving the web UI rather than the API
+var pages = []string{"/", "/admin", "/admin/dashboard", "/static/"}
+
+// routeMethods walks router and returns the methods of every API route,
+// keyed by path template
+func routeMethods(t *testing.T, router *mux.Router) map[string][]string {
+	t.Helper()
+	routes := make(map[string][]string)
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil // A subrouter; its routes are walked separately
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		if slices.Contains(pages, path) {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{http.MethodGet} // Only /ws, which is upgraded from a GET
+		}
+		for _, method := range methods {
+			routes[path] = append(routes[path], strings.ToLower(method))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walking routes: %v", err)
+	}
+	return routes
+}
+
+// specMethods fetches the OpenAPI document from server and returns the
+// methods of every path it describes
+func specMethods(t *testing.T, server *httptest.Server) map[string][]string {
+	t.Helper()
+	resp, err := server.Client().Get(server.URL + "/api/openapi.json")
+	if err != nil {
+		t.Fatalf("fetching the OpenAPI document: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("decoding the OpenAPI document: %v", err)
+	}
+
+	operations := make(map[string][]string)
+	for path, item := range spec.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue // Shared by the path's operations
+			}
+			operations[path] = append(operations[path], method)
+		}
+	}
+	return operations
+}
+
+// TestOpenAPIMatchesRoutes checks that openapi.json documents exactly the
+// routes and methods registered by NewRouter
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	server := newServer(t)
+	router, ok := server.Config.Handler.(*mux.Router)
+	if !ok {
+		t.Fatalf("server handler is %T, want *mux.Router", server.Config.Handler)
+	}
+
+	routes := routeMethods(t, router)
+	documented := specMethods(t, server)
+
+	for path, methods := range routes {
+		for _, method := range methods {
+			if !slices.Contains(documented[path], method) {
+				t.Errorf("%s %s is routed but not in openapi.json", strings.ToUpper(method), path)
+			}
+		}
+	}
+	for path, methods := range documented {
+		for _, method := range methods {
+			if !slices.Contains(routes[path], method) {
+				t.Errorf("%s %s is in openapi.json but not routed", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/handler"
+	"fileprintapp/internal/health"
+	"fileprintapp/internal/metrics"
+	"fileprintapp/internal/middleware"
+	"fileprintapp/internal/ratelimit"
+	"fileprintapp/internal/usecase"
+	ws "fileprintapp/internal/websocket"
+	"log/slog"
+	"net/http"
+	"net/netip"
+
+	"github.com/gorilla/mux"
+)
+
+// Services are the use cases shared by the HTTP server and the admin commands
+type Services struct {
+	Files    *usecase.FileService
+	Folders  *usecase.FolderService
+	Auth     *usecase.AuthService
+	Search   *usecase.SearchService
+	Settings *usecase.SettingsService
+}
+
+// NewServices builds the use cases on top of a backend
+func NewServices(cfg *config.Config, b *Backend) *Services {
+	settings := usecase.NewSettingsService(b.Settings, DefaultSettings(cfg))
+	return &Services{
+		Files: usecase.NewFileService(
+			b.Files,
+			b.Folders,
+			b.UnitOfWork,
+			cfg.StoragePath,
+			settings,
+			usecase.FolderQuota{MaxFiles: cfg.FolderMaxFiles, MaxBytes: cfg.FolderMaxBytes},
+		),
+		Folders:  usecase.NewFolderService(b.Folders, b.Files, b.UnitOfWork),
+		Auth:     usecase.NewAuthService(b.Admins, cfg.JWTSecret),
+		Search:   usecase.NewSearchService(b.Search),
+		Settings: settings,
+	}
+}
+
+// DefaultSettings are the shop settings in effect until an admin saves some
+// Upload limits come from MAX_FILE_SIZE and ALLOWED_EXTENSIONS
+func DefaultSettings(cfg *config.Config) domain.Settings {
+	return domain.Settings{
+		ShopName:          "Ikon_Printz",
+		MaxFileSize:       cfg.MaxFileSize,
+		AllowedExtensions: cfg.AllowedExtensions,
+	}
+}
+
+// NewHub creates the WebSocket hub
+// With EVENT_BUS=postgres, events are relayed through LISTEN/NOTIFY so
+// every server instance sees every upload
+func NewHub(cfg *config.Config, b *Backend) *ws.Hub {
+	if cfg.EventBus == "postgres" && cfg.DBDriver == "postgres" {
+		slog.Info("using Postgres LISTEN/NOTIFY event bus")
+		return ws.NewHubWithBus(ws.NewPostgresBus(b.DB, PostgresConfig(cfg).DSN()))
+	}
+	return ws.NewHub()
+}
+
+// NewMetricsHandler serves Prometheus metrics for the hub and database pool,
+// behind METRICS_TOKEN when one is set
+func NewMetricsHandler(cfg *config.Config, b *Backend, hub *ws.Hub) http.Handler {
+	return middleware.MetricsToken(cfg.MetricsToken)(metrics.Handler(hub, b.DB))
+}
+
+// RateLimits throttle the public routes that write data
+type RateLimits struct {
+	PerIP          *ratelimit.Limiter // Uploads and folder creations per client IP
+	PerFolder      *ratelimit.Limiter // Uploads per folder
+	TrustedProxies []netip.Prefix     // Proxies whose X-Forwarded-For names the client
+}
+
+// NewRateLimits builds the limiters from RATE_LIMIT_PER_IP, RATE_LIMIT_PER_FOLDER
+// and TRUSTED_PROXIES
+func NewRateLimits(cfg *config.Config) *RateLimits {
+	proxies := make([]netip.Prefix, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		proxies = append(proxies, netip.MustParsePrefix(proxy)) // Validate normalized them to CIDR
+	}
+	return &RateLimits{
+		PerIP:          ratelimit.New(cfg.RateLimitPerIP),
+		PerFolder:      ratelimit.New(cfg.RateLimitPerFolder),
+		TrustedProxies: proxies,
+	}
+}
+
+// NewRouter registers every page and API route
+// The OpenAPI document in internal/handler/openapi.json describes these routes
+func NewRouter(s *Services, hub *ws.Hub, metricsHandler http.Handler, healthService *health.Service, limits *RateLimits) *mux.Router {
+	authHandler := handler.NewAuthHandler(s.Auth)
+	fileHandler := handler.NewFileHandler(s.Files, s.Folders, hub, limits.PerFolder)
+	folderHandler := handler.NewFolderHandler(s.Folders, hub)
+	wsHandler := handler.NewWebSocketHandler(hub, s.Auth, s.Folders)
+	searchHandler := handler.NewSearchHandler(s.Search)
+	settingsHandler := handler.NewSettingsHandler(s.Settings, hub)
+	healthHandler := handler.NewHealthHandler(healthService)
+	authMiddleware := middleware.NewAuthMiddleware(s.Auth)
+
+	r := mux.NewRouter()
+
+	// Tags every request with an ID that follows it into logs and events
+	r.Use(middleware.RequestID)
+
+	// Traces each request, joining the caller's trace when one is propagated;
+	// runs before AccessLog so access log lines carry the trace ID
+	r.Use(middleware.Tracing)
+	r.Use(middleware.AccessLog)
+
+	// Allows cross-origin requests (important for hosted frontends)
+	r.Use(middleware.CORS)
+
+	// Counts requests and their durations per route
+	r.Use(middleware.Metrics)
+
+	// Static files (HTML, CSS, JS)
+	r.PathPrefix("/static/").Handler(
+		http.StripPrefix("/static/", http.FileServer(http.Dir("web/static"))),
+	)
+
+	// === PUBLIC ROUTES (No authentication required) ===
+
+	// Upload page for customers, admin login (not linked from the upload page) and dashboard
+	r.HandleFunc("/", servePage("web/static/index.html")).Methods("GET")
+	r.HandleFunc("/admin", servePage("web/static/admin-login.html")).Methods("GET")
+	r.HandleFunc("/admin/dashboard", servePage("web/static/admin-dashboard.html")).Methods("GET")
+
+	// Anyone can write here, so each client IP gets a budget (429 when it runs out)
+	perIP := middleware.RateLimit(limits.PerIP, limits.TrustedProxies)
+	r.Handle("/api/upload", perIP(http.HandlerFunc(fileHandler.UploadFile))).Methods("POST")
+	r.Handle("/api/folders", perIP(http.HandlerFunc(folderHandler.CreateFolder))).Methods("POST")
+	r.HandleFunc("/api/admin/login", authHandler.Login).Methods("POST") // Returns a JWT
+	r.HandleFunc("/api/openapi.json", handler.OpenAPI).Methods("GET")
+
+	// Prometheus scraping; protected by METRICS_TOKEN instead of a JWT
+	r.Handle("/metrics", metricsHandler).Methods("GET")
+
+	// Probes for load balancers and orchestrators
+	r.HandleFunc("/healthz", healthHandler.Live).Methods("GET", "HEAD")
+	r.HandleFunc("/readyz", healthHandler.Ready).Methods("GET", "HEAD")
+
+	// Real-time updates; the handler authenticates admins itself
+	r.HandleFunc("/ws", wsHandler.HandleWebSocket)
+
+	// === PROTECTED ROUTES (Require JWT authentication) ===
+	adminRouter := r.PathPrefix("/api").Subrouter()
+	adminRouter.Use(authMiddleware.Authenticate)
+	adminRouter.HandleFunc("/files", fileHandler.ListFiles).Methods("GET")
+	adminRouter.HandleFunc("/folders", folderHandler.ListFolders).Methods("GET")
+	adminRouter.HandleFunc("/folders/{id}", folderHandler.GetFolder).Methods("GET")
+	adminRouter.HandleFunc("/folders/{id}", folderHandler.RenameFolder).Methods("PATCH")
+	adminRouter.HandleFunc("/folders/{id}", folderHandler.DeleteFolder).Methods("DELETE")
+	adminRouter.HandleFunc("/search", searchHandler.Search).Methods("GET")
+	adminRouter.HandleFunc("/files/{id}", fileHandler.DeleteFile).Methods("DELETE")
+	adminRouter.HandleFunc("/files/{id}/view", fileHandler.ViewFile).Methods("GET")
+	adminRouter.HandleFunc("/settings", settingsHandler.GetSettings).Methods("GET")
+	adminRouter.HandleFunc("/settings", settingsHandler.UpdateSettings).Methods("PUT")
+	adminRouter.HandleFunc("/settings/history", settingsHandler.ListSettingsHistory).Methods("GET")
+	adminRouter.HandleFunc("/admin/diagnostics", healthHandler.Diagnostics).Methods("GET")
+
+	return r
+}
+
+// servePage serves one HTML page
+func servePage(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, path)
+	}
+}
@@ -0,0 +1,195 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fileprintapp/internal/app"
+	"fileprintapp/internal/config"
+	"fileprintapp/internal/tracing"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exporter records every span ended by the tests in this package
+var exporter = tracetest.NewInMemoryExporter()
+
+func TestMain(m *testing.M) {
+	// No endpoint: only the propagators are installed
+	if _, err := tracing.Setup(context.Background(), "", "test"); err != nil {
+		panic(err)
+	}
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	os.Exit(m.Run())
+}
+
+// newServer serves the full router on the in-memory backend
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Environment:       "development",
+		AdminUsername:     "admin",
+		AdminPassword:     "tracing-test-password",
+		JWTSecret:         "tracing-test-secret",
+		MaxFileSize:       1 << 20,
+		AllowedExtensions: []string{"png"},
+		StorageType:       "local",
+		StoragePath:       t.TempDir(),
+		DBDriver:          "memory",
+		EventBus:          "local",
+	}
+
+	backend, err := app.Open(cfg)
+	if err != nil {
+		t.Fatalf("opening backend: %v", err)
+	}
+	services := app.NewServices(cfg, backend)
+	hub := app.NewHub(cfg, backend)
+	ctx, stopHub := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+
+	router := app.NewRouter(services, hub,
+		app.NewMetricsHandler(cfg, backend, hub),
+		app.NewHealthService(cfg, backend, services, hub),
+		app.NewRateLimits(cfg),
+	)
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		server.Close()
+		stopHub()
+		<-hub.Done()
+		backend.Close()
+	})
+	return server
+}
+
+// createFolder creates a folder through the API and returns its ID
+func createFolder(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	resp, err := server.Client().Post(server.URL+"/api/folders", "application/json", strings.NewReader(`{"name":"Traced"}`))
+	if err != nil {
+		t.Fatalf("creating folder: %v", err)
+	}
+	defer resp.Body.Close()
+	var folder struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&folder); err != nil || folder.ID == "" {
+		t.Fatalf("creating folder: status %d, %v", resp.StatusCode, err)
+	}
+	return folder.ID
+}
+
+// upload posts a small image to folderID with the given extra headers
+func upload(t *testing.T, server *httptest.Server, folderID string, header http.Header) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("folder_id", folderID)
+	form.WriteField("folder_name", "Traced")
+	part, err := form.CreateFormFile("file", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte("\x89PNG\r\n\x1a\n"))
+	form.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/upload", &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("uploading: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("upload status = %d, want 200", resp.StatusCode)
+	}
+}
+
+// findSpan returns the one recorded span with the given name
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	var found []tracetest.SpanStub
+	for _, span := range spans {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("recorded %d %q spans, want 1", len(found), name)
+	}
+	return found[0]
+}
+
+func TestTracingUpload(t *testing.T) {
+	// A caller's trace, as a proxy would propagate it
+	const (
+		callerTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+		callerSpan  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		traceparent string
+	}{
+		{"new trace", ""},
+		{"propagated traceparent", "00-" + callerTrace + "-" + callerSpan + "-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			folderID := createFolder(t, server)
+
+			exporter.Reset()
+			header := http.Header{}
+			if tt.traceparent != "" {
+				header.Set("Traceparent", tt.traceparent)
+			}
+			upload(t, server, folderID, header)
+
+			spans := exporter.GetSpans()
+			serverSpan := findSpan(t, spans, "POST /api/upload")
+			uploadSpan := findSpan(t, spans, "FileService.UploadFile")
+
+			if serverSpan.SpanKind != trace.SpanKindServer {
+				t.Errorf("server span kind = %v, want server", serverSpan.SpanKind)
+			}
+			if uploadSpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() ||
+				uploadSpan.SpanContext.TraceID() != serverSpan.SpanContext.TraceID() {
+				t.Errorf("FileService.UploadFile span isn't a child of the server span")
+			}
+			if writeSpan := findSpan(t, spans, "write file"); writeSpan.Parent.SpanID() != uploadSpan.SpanContext.SpanID() {
+				t.Errorf("write file span isn't a child of the FileService.UploadFile span")
+			}
+
+			if tt.traceparent == "" {
+				if serverSpan.Parent.IsValid() {
+					t.Errorf("server span has parent %s, want a new trace", serverSpan.Parent.SpanID())
+				}
+				return
+			}
+			if got := serverSpan.SpanContext.TraceID().String(); got != callerTrace {
+				t.Errorf("server span trace ID = %s, want the caller's %s", got, callerTrace)
+			}
+			if got := serverSpan.Parent.SpanID().String(); got != callerSpan || !serverSpan.Parent.IsRemote() {
+				t.Errorf("server span parent = %s (remote %v), want the caller's span %s", got, serverSpan.Parent.IsRemote(), callerSpan)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -27,69 +29,154 @@ type Config struct {
 	MaxFileSize       int64    // Maximum file size in bytes
 	AllowedExtensions []string // Allowed file extensions (e.g., ["pdf", "jpg"])
 
+	// Limits on the public upload routes (0 disables each)
+	RateLimitPerIP     int64    // Uploads and folder creations per minute from one client IP
+	RateLimitPerFolder int64    // Uploads per minute into one folder
+	FolderMaxFiles     int64    // Files one folder may hold
+	FolderMaxBytes     int64    // Bytes one folder may hold
+	TrustedProxies     []string // Proxies whose X-Forwarded-For is believed, as IPs or CIDRs
+
 	// Storage configuration
-	StorageType string // "local" or "cloud"
+	StorageType string // "local" (uploads under StoragePath; no other backend yet)
 	StoragePath string // Path for local storage or cloud config
+	MinFreeDisk int64  // Free bytes below which the server reports itself not ready (0 disables the check)
+
+	// Database configuration
+	DBDriver   string // "postgres" (Neon, default), "sqlite" (single-box installs) or "memory" (development, lost on restart)
+	SQLitePath string // SQLite database file when DBDriver is "sqlite"
 
-	// Database configuration (Neon PostgreSQL)
+	// PostgreSQL connection (Neon)
 	DBHost     string // Database host from Neon
 	DBPort     string // Database port (usually 5432)
 	DBName     string // Database name
 	DBUser     string // Database username
 	DBPassword string // Database password
 	DBSSLMode  string // SSL mode ("require" for Neon)
+
+	// Real-time events
+	EventBus string // "local" (single instance) or "postgres" (LISTEN/NOTIFY across instances)
+
+	// Shutdown
+	ShutdownTimeout time.Duration // How long shutdown waits for in-flight requests and uploads
+
+	// Monitoring
+	MetricsToken string // Bearer token required by /metrics (empty leaves it open)
+	LogLevel     string // "debug", "info", "warn" or "error"
+	OTLPEndpoint string // OTLP/HTTP collector receiving traces (empty disables export)
 }
 
-// LoadConfig loads configuration from environment variables
+// Defaults for the two secrets, accepted only outside production
+const (
+	defaultAdminPassword = "changeme123"
+	defaultJWTSecret     = "change-this-secret-key-in-production"
+)
+
+// LoadConfig loads configuration from environment variables and, when
+// CONFIG_FILE is set, a YAML or TOML file
 // It first attempts to load from .env file, then falls back to system env vars
+// Environment variables override the file, which overrides the defaults.
 // This is safe for production as hosting platforms set environment variables
 // Returns:
-//   - *Config: Fully populated configuration object
-//   - error: Currently always nil, but kept for future validation
+//   - *Config: Fully populated and validated configuration object
+//   - error: Every invalid or insecure setting found, joined with errors.Join
 func LoadConfig() (*Config, error) {
 	// Attempt to load .env file
 	// In production (Railway, Fly.io, etc.), this file won't exist
 	// and env vars will be loaded from the platform instead
 	if err := godotenv.Load(); err != nil {
-		log.Println("ℹ️  No .env file found, using system environment variables")
+		slog.Info("no .env file found, using system environment variables")
 	}
 
-	// Parse max file size from string to int64
-	// Default: 10MB (10485760 bytes)
-	maxFileSize, _ := strconv.ParseInt(getEnv("MAX_FILE_SIZE", "10485760"), 10, 64)
-	
-	// Parse allowed extensions from comma-separated string
-	// Default: common image and document formats
-	extensions := strings.Split(getEnv("ALLOWED_EXTENSIONS", "jpg,jpeg,png,pdf,gif"), ",")
+	// Optional configuration file (e.g., config.yaml)
+	// Keys are the environment variable names in lower case
+	src, err := newSource(getEnv("CONFIG_FILE", ""))
+	if err != nil {
+		return nil, err
+	}
 
-	// Build and return configuration object
-	return &Config{
+	// Build configuration object
+	cfg := &Config{
 		// Server settings
-		Port:        getEnv("PORT", "8080"),
-		Host:        getEnv("HOST", "0.0.0.0"), // 0.0.0.0 allows external connections
-		Environment: getEnv("ENVIRONMENT", "production"),
+		Port:        src.string("PORT", "8080"),
+		Host:        src.string("HOST", "0.0.0.0"), // 0.0.0.0 allows external connections
+		Environment: src.string("ENVIRONMENT", "production"),
 
 		// Admin authentication
-		AdminUsername: getEnv("ADMIN_USERNAME", "admin"),
-		AdminPassword: getEnv("ADMIN_PASSWORD", "changeme123"),
-		JWTSecret:     getEnv("JWT_SECRET", "change-this-secret-key-in-production"),
+		AdminUsername: src.string("ADMIN_USERNAME", "admin"),
+		AdminPassword: src.string("ADMIN_PASSWORD", defaultAdminPassword),
+		JWTSecret:     src.string("JWT_SECRET", defaultJWTSecret),
 
 		// File upload configuration
-		MaxFileSize:       maxFileSize,
-		AllowedExtensions: extensions,
+		// Default: 10MB (10485760 bytes) and common image and document formats
+		MaxFileSize:       src.int64("MAX_FILE_SIZE", 10485760),
+		AllowedExtensions: src.list("ALLOWED_EXTENSIONS", "jpg,jpeg,png,pdf,gif"),
+
+		// Limits on anonymous uploads, so nobody can fill the disk or the dashboard
+		// Default: 60 requests a minute per IP, 120 uploads a minute and
+		// 500 files or 1GB per folder
+		RateLimitPerIP:     src.int64("RATE_LIMIT_PER_IP", 60),
+		RateLimitPerFolder: src.int64("RATE_LIMIT_PER_FOLDER", 120),
+		FolderMaxFiles:     src.int64("FOLDER_MAX_FILES", 500),
+		FolderMaxBytes:     src.int64("FOLDER_MAX_BYTES", 1073741824),
+
+		// Set to the load balancer's addresses (e.g., "10.0.0.0/8") so limits
+		// apply to customers rather than to the proxy
+		TrustedProxies: src.list("TRUSTED_PROXIES", ""),
 
 		// Storage settings
-		StorageType: getEnv("STORAGE_TYPE", "local"),
-		StoragePath: getEnv("STORAGE_PATH", "./uploads"),
-
-		// Database configuration (Neon PostgreSQL)
-		DBHost:     getEnv("DB_HOST", ""),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBName:     getEnv("DB_NAME", "neondb"),
-		DBUser:     getEnv("DB_USER", ""),
-		DBPassword: getEnv("DB_PASSWORD", ""),
-		DBSSLMode:  getEnv("DB_SSL_MODE", "require"), // Always require for Neon
-	}, nil
+		StorageType: src.string("STORAGE_TYPE", "local"),
+		StoragePath: src.string("STORAGE_PATH", "./uploads"),
+
+		// Readiness fails (so no new traffic is routed here) when the disk
+		// holding STORAGE_PATH has less free space than this
+		// Default: 100MB
+		MinFreeDisk: src.int64("MIN_FREE_DISK", 104857600),
+
+		// Database configuration
+		DBDriver:   src.string("DB_DRIVER", "postgres"),
+		SQLitePath: src.string("SQLITE_PATH", "./data/ikonprintzz.db"),
+
+		// PostgreSQL connection (Neon)
+		DBHost:     src.string("DB_HOST", ""),
+		DBPort:     src.string("DB_PORT", "5432"),
+		DBName:     src.string("DB_NAME", "neondb"),
+		DBUser:     src.string("DB_USER", ""),
+		DBPassword: src.string("DB_PASSWORD", ""),
+		DBSSLMode:  src.string("DB_SSL_MODE", "require"), // Always require for Neon
+
+		// Real-time events
+		// Use "postgres" when running more than one instance behind a load balancer
+		EventBus: src.string("EVENT_BUS", "local"),
+
+		// Graceful shutdown deadline
+		// Default: 30 seconds, which fits inside most platforms' SIGTERM grace period
+		ShutdownTimeout: src.duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+		// Prometheus scraping
+		// Set a token when /metrics is reachable from the internet
+		MetricsToken: src.string("METRICS_TOKEN", ""),
+
+		// Logs are JSON in production and text otherwise
+		LogLevel: src.string("LOG_LEVEL", "info"),
+
+		// OpenTelemetry collector, e.g. http://localhost:4318
+		// Traces are only exported when this is set
+		OTLPEndpoint: src.string("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+	}
+
+	// Report parse errors, unknown file keys and invalid values together
+	errs := append(src.errs, src.unknownKeys()...)
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if cfg.AdminPassword == defaultAdminPassword || cfg.JWTSecret == defaultJWTSecret {
+		slog.Warn("using the default ADMIN_PASSWORD or JWT_SECRET; set both before deploying")
+	}
+	return cfg, nil
 }
 
 // getEnv retrieves an environment variable or returns a default value
@@ -106,6 +193,16 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// UsesSQLite reports whether data is stored in an embedded SQLite file
+func (c *Config) UsesSQLite() bool {
+	return c.DBDriver == "sqlite"
+}
+
+// UsesMemory reports whether data is kept in memory only
+func (c *Config) UsesMemory() bool {
+	return c.DBDriver == "memory"
+}
+
 // IsDevelopment checks if we're running in development mode
 // Useful for conditional logging, debugging, etc.
 func (c *Config) IsDevelopment() bool {
@@ -117,3 +214,49 @@ func (c *Config) IsDevelopment() bool {
 func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
+
+// Summary lists every setting by its environment variable name, for the
+// admin diagnostics report
+// Secrets are never included: they are reported as "[REDACTED]" when set
+// Returns:
+//   - map[string]string: Setting values keyed by environment variable name
+func (c *Config) Summary() map[string]string {
+	secret := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return "[REDACTED]"
+	}
+
+	return map[string]string{
+		"PORT":                        c.Port,
+		"HOST":                        c.Host,
+		"ENVIRONMENT":                 c.Environment,
+		"ADMIN_USERNAME":              c.AdminUsername,
+		"ADMIN_PASSWORD":              secret(c.AdminPassword),
+		"JWT_SECRET":                  secret(c.JWTSecret),
+		"MAX_FILE_SIZE":               strconv.FormatInt(c.MaxFileSize, 10),
+		"ALLOWED_EXTENSIONS":          strings.Join(c.AllowedExtensions, ","),
+		"RATE_LIMIT_PER_IP":           strconv.FormatInt(c.RateLimitPerIP, 10),
+		"RATE_LIMIT_PER_FOLDER":       strconv.FormatInt(c.RateLimitPerFolder, 10),
+		"FOLDER_MAX_FILES":            strconv.FormatInt(c.FolderMaxFiles, 10),
+		"FOLDER_MAX_BYTES":            strconv.FormatInt(c.FolderMaxBytes, 10),
+		"TRUSTED_PROXIES":             strings.Join(c.TrustedProxies, ","),
+		"STORAGE_TYPE":                c.StorageType,
+		"STORAGE_PATH":                c.StoragePath,
+		"MIN_FREE_DISK":               strconv.FormatInt(c.MinFreeDisk, 10),
+		"DB_DRIVER":                   c.DBDriver,
+		"SQLITE_PATH":                 c.SQLitePath,
+		"DB_HOST":                     c.DBHost,
+		"DB_PORT":                     c.DBPort,
+		"DB_NAME":                     c.DBName,
+		"DB_USER":                     c.DBUser,
+		"DB_PASSWORD":                 secret(c.DBPassword),
+		"DB_SSL_MODE":                 c.DBSSLMode,
+		"EVENT_BUS":                   c.EventBus,
+		"SHUTDOWN_TIMEOUT":            c.ShutdownTimeout.String(),
+		"METRICS_TOKEN":               secret(c.MetricsToken),
+		"LOG_LEVEL":                   c.LogLevel,
+		"OTEL_EXPORTER_OTLP_ENDPOINT": c.OTLPEndpoint,
+	}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// source looks up configuration values by environment variable name
+// Environment variables win over the configuration file, which wins over
+// the defaults. Values that can't be parsed are collected in errs so every
+// problem is reported at once.
+type source struct {
+	file map[string]string // Values from CONFIG_FILE, keyed by upper-case name
+	path string            // CONFIG_FILE, for error messages
+	used map[string]bool   // Names looked up so far
+	errs []error
+}
+
+// newSource creates a source, reading the configuration file if path is set
+// Parameters:
+//   - path: YAML (.yaml, .yml) or TOML (.toml) file; empty for none
+//
+// Returns:
+//   - *source: Ready for lookups
+//   - error: If the file can't be read or parsed
+func newSource(path string) (*source, error) {
+	s := &source{path: path, used: make(map[string]bool)}
+	if path == "" {
+		return s, nil
+	}
+
+	file, err := readConfigFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+	s.file = file
+	return s, nil
+}
+
+// readConfigFile parses a flat YAML or TOML file into upper-case names
+// Keys are the environment variable names in any case (port, db_driver, ...);
+// lists such as allowed_extensions may be written as arrays.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported format %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[strings.ToUpper(key)] = strings.Join(items, ",")
+		case map[string]interface{}:
+			return nil, fmt.Errorf("%s: expected a single value, not a table", key)
+		default:
+			values[strings.ToUpper(key)] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
+
+// lookup returns the value for name, or "" if it is unset everywhere
+// Empty values count as unset, as they always have for environment variables
+func (s *source) lookup(name string) string {
+	s.used[name] = true
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return s.file[name]
+}
+
+// string returns the value for name or defaultValue
+func (s *source) string(name, defaultValue string) string {
+	if value := s.lookup(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// int64 returns the value for name as an integer or defaultValue
+// A malformed value is recorded as an error
+func (s *source) int64(name string, defaultValue int64) int64 {
+	value := s.lookup(name)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not a whole number", name, value))
+		return defaultValue
+	}
+	return n
+}
+
+// duration returns the value for name as a duration (e.g., "30s") or defaultValue
+// A malformed value is recorded as an error
+func (s *source) duration(name string, defaultValue time.Duration) time.Duration {
+	value := s.lookup(name)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: %q is not a duration such as 30s or 2m", name, value))
+		return defaultValue
+	}
+	return d
+}
+
+// list returns the comma-separated value for name, or defaultValue, with
+// each item trimmed
+func (s *source) list(name, defaultValue string) []string {
+	items := strings.Split(s.string(name, defaultValue), ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+// unknownKeys reports configuration file keys that no setting looked up,
+// which are almost always typos
+func (s *source) unknownKeys() []error {
+	var names []string
+	for name := range s.file {
+		if !s.used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("CONFIG_FILE %s: unknown setting %q", s.path, strings.ToLower(name)))
+	}
+	return errs
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// clearEnv unsets every setting, and CONFIG_FILE, for the rest of the test
+// Empty values count as unset.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	for name := range (&Config{}).Summary() {
+		t.Setenv(name, "")
+	}
+}
+
+// writeConfigFile writes a configuration file named name and points
+// CONFIG_FILE at it
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"yaml", "config.yaml", `
+environment: development
+db_driver: memory
+port: 9000
+log_level: debug
+allowed_extensions: [pdf, png]
+shutdown_timeout: 10s
+`},
+		{"toml", "config.toml", `
+ENVIRONMENT = "development"
+DB_DRIVER = "memory"
+PORT = 9000
+LOG_LEVEL = "debug"
+ALLOWED_EXTENSIONS = ["pdf", "png"]
+SHUTDOWN_TIMEOUT = "10s"
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			writeConfigFile(t, tt.file, tt.content)
+			t.Setenv("PORT", "9100")
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() = %v", err)
+			}
+			if cfg.Port != "9100" {
+				t.Errorf("Port = %q, want the environment's 9100", cfg.Port)
+			}
+			if cfg.LogLevel != "debug" || cfg.ShutdownTimeout != 10*time.Second {
+				t.Errorf("LogLevel, ShutdownTimeout = %q, %v, want the file's debug, 10s", cfg.LogLevel, cfg.ShutdownTimeout)
+			}
+			if want := []string{"pdf", "png"}; !slices.Equal(cfg.AllowedExtensions, want) {
+				t.Errorf("AllowedExtensions = %q, want the file's %q", cfg.AllowedExtensions, want)
+			}
+			if cfg.Host != "0.0.0.0" || cfg.MaxFileSize != 10485760 {
+				t.Errorf("Host, MaxFileSize = %q, %d, want the defaults", cfg.Host, cfg.MaxFileSize)
+			}
+		})
+	}
+}
+
+func TestLoadConfigWithoutFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("MAX_FILE_SIZE", "2048")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() = %v", err)
+	}
+	if cfg.DBDriver != "sqlite" || cfg.MaxFileSize != 2048 || cfg.Port != "8080" {
+		t.Errorf("DBDriver, MaxFileSize, Port = %q, %d, %q", cfg.DBDriver, cfg.MaxFileSize, cfg.Port)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		env     map[string]string
+		want    []string
+	}{
+		{
+			name:    "malformed yaml",
+			file:    "config.yaml",
+			content: "port: [9000\n",
+			want:    []string{"CONFIG_FILE"},
+		},
+		{
+			name:    "malformed toml",
+			file:    "config.toml",
+			content: "PORT = \n",
+			want:    []string{"CONFIG_FILE"},
+		},
+		{
+			name:    "unsupported format",
+			file:    "config.json",
+			content: `{"port": 9000}`,
+			want:    []string{`unsupported format ".json"`},
+		},
+		{
+			name:    "nested table",
+			file:    "config.yaml",
+			content: "database:\n  driver: sqlite\n",
+			want:    []string{"database: expected a single value"},
+		},
+		{
+			name:    "unknown keys",
+			file:    "config.yaml",
+			content: "environment: development\ndb_driver: memory\nprot: 9000\nlog_levle: debug\n",
+			want:    []string{`unknown setting "prot"`, `unknown setting "log_levle"`},
+		},
+		{
+			name:    "malformed values",
+			file:    "config.yaml",
+			content: "environment: development\ndb_driver: memory\nmax_file_size: 10MB\n",
+			env:     map[string]string{"SHUTDOWN_TIMEOUT": "soon"},
+			want:    []string{`MAX_FILE_SIZE: "10MB" is not a whole number`, `SHUTDOWN_TIMEOUT: "soon" is not a duration`},
+		},
+		{
+			name:    "parse, file and validation errors together",
+			file:    "config.yaml",
+			content: "environment: development\ndb_driver: memory\ncolour: blue\nport: 0\n",
+			env:     map[string]string{"RATE_LIMIT_PER_IP": "many"},
+			want:    []string{"RATE_LIMIT_PER_IP:", `unknown setting "colour"`, "PORT:"},
+		},
+		{
+			name: "production defaults",
+			env:  map[string]string{"DB_HOST": "db.example.com"},
+			want: []string{"ADMIN_PASSWORD: the default password", "JWT_SECRET: the default secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			if tt.file != "" {
+				writeConfigFile(t, tt.file, tt.content)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := LoadConfig()
+			if cfg != nil {
+				t.Errorf("LoadConfig() returned a config along with the error")
+			}
+			wantErrors(t, err, tt.want...)
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+	_, err := LoadConfig()
+	wantErrors(t, err, "CONFIG_FILE", "missing.yaml")
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// minAdminPasswordLength matches the rule for passwords set with the CLI
+const minAdminPasswordLength = 8
+
+// sslModes are the values lib/pq accepts for sslmode
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate checks every setting and, in production, refuses insecure ones
+// LoadConfig calls it; call it yourself when building a Config by hand
+// Allowed extensions are normalized to lower case without a leading dot,
+// trusted proxies to CIDR prefixes and LOG_LEVEL to lower case
+// Returns:
+//   - error: nil if valid, otherwise every problem found joined with errors.Join
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(name, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", name, fmt.Sprintf(format, args...)))
+	}
+
+	// Server settings
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		fail("PORT", "%q is not a port number (1-65535)", c.Port)
+	}
+	if strings.TrimSpace(c.Host) == "" {
+		fail("HOST", "must not be empty")
+	}
+	if !oneOf(c.Environment, "development", "production") {
+		fail("ENVIRONMENT", "%q must be development or production", c.Environment)
+	}
+
+	// Admin authentication
+	if strings.TrimSpace(c.AdminUsername) == "" {
+		fail("ADMIN_USERNAME", "must not be empty")
+	}
+	if len(c.AdminPassword) < minAdminPasswordLength {
+		fail("ADMIN_PASSWORD", "must be at least %d characters", minAdminPasswordLength)
+	}
+	if c.JWTSecret == "" {
+		fail("JWT_SECRET", "must not be empty")
+	}
+
+	// File upload settings
+	if c.MaxFileSize <= 0 {
+		fail("MAX_FILE_SIZE", "must be a positive number of bytes")
+	}
+	if c.MinFreeDisk < 0 {
+		fail("MIN_FREE_DISK", "must not be negative")
+	}
+	if len(c.AllowedExtensions) == 0 {
+		fail("ALLOWED_EXTENSIONS", "must list at least one extension")
+	}
+	for i, ext := range c.AllowedExtensions {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext == "" || strings.ContainsAny(ext, "./\\ ") {
+			fail("ALLOWED_EXTENSIONS", "%q is not a file extension", c.AllowedExtensions[i])
+			continue
+		}
+		c.AllowedExtensions[i] = ext // Uploads are matched in lower case
+	}
+
+	// Public upload limits
+	limits := []struct {
+		name  string
+		value int64
+	}{
+		{"RATE_LIMIT_PER_IP", c.RateLimitPerIP},
+		{"RATE_LIMIT_PER_FOLDER", c.RateLimitPerFolder},
+		{"FOLDER_MAX_FILES", c.FolderMaxFiles},
+		{"FOLDER_MAX_BYTES", c.FolderMaxBytes},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			fail(limit.name, "must not be negative (0 disables the limit)")
+		}
+	}
+	proxies := make([]string, 0, len(c.TrustedProxies))
+	for _, proxy := range c.TrustedProxies {
+		if proxy = strings.TrimSpace(proxy); proxy == "" {
+			continue
+		}
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			fail("TRUSTED_PROXIES", "%q is not an IP address or CIDR range", proxy)
+			continue
+		}
+		proxies = append(proxies, prefix.String())
+	}
+	c.TrustedProxies = proxies
+
+	// Storage settings
+	if c.StorageType != "local" {
+		fail("STORAGE_TYPE", "%q is not supported, use local", c.StorageType)
+	}
+	if strings.TrimSpace(c.StoragePath) == "" {
+		fail("STORAGE_PATH", "must not be empty")
+	}
+
+	// Database settings
+	switch c.DBDriver {
+	case "postgres":
+		if port, err := strconv.Atoi(c.DBPort); err != nil || port < 1 || port > 65535 {
+			fail("DB_PORT", "%q is not a port number (1-65535)", c.DBPort)
+		}
+		if c.DBName == "" {
+			fail("DB_NAME", "must not be empty")
+		}
+		if !oneOf(c.DBSSLMode, sslModes...) {
+			fail("DB_SSL_MODE", "%q must be one of %s", c.DBSSLMode, strings.Join(sslModes, ", "))
+		}
+	case "sqlite":
+		if strings.TrimSpace(c.SQLitePath) == "" {
+			fail("SQLITE_PATH", "must not be empty")
+		}
+	case "memory":
+	default:
+		fail("DB_DRIVER", "%q must be postgres, sqlite or memory", c.DBDriver)
+	}
+
+	// Real-time events
+	if !oneOf(c.EventBus, "local", "postgres") {
+		fail("EVENT_BUS", "%q must be local or postgres", c.EventBus)
+	} else if c.EventBus == "postgres" && c.DBDriver != "postgres" {
+		fail("EVENT_BUS", "postgres requires DB_DRIVER=postgres")
+	}
+
+	// Shutdown
+	if c.ShutdownTimeout <= 0 {
+		fail("SHUTDOWN_TIMEOUT", "must be positive")
+	}
+
+	// Monitoring
+	c.LogLevel = strings.ToLower(strings.TrimSpace(c.LogLevel))
+	if !oneOf(c.LogLevel, "debug", "info", "warn", "error") {
+		fail("LOG_LEVEL", "%q must be debug, info, warn or error", c.LogLevel)
+	}
+	if c.OTLPEndpoint != "" {
+		if u, err := url.Parse(c.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("OTEL_EXPORTER_OTLP_ENDPOINT", "%q is not an http(s) URL", c.OTLPEndpoint)
+		}
+	}
+
+	// Production refuses the shipped defaults and settings that lose data
+	if c.IsProduction() {
+		if c.AdminPassword == defaultAdminPassword {
+			fail("ADMIN_PASSWORD", "the default password is not allowed in production")
+		}
+		if c.JWTSecret == defaultJWTSecret {
+			fail("JWT_SECRET", "the default secret is not allowed in production")
+		}
+		if c.DBDriver == "postgres" && c.DBHost == "" {
+			fail("DB_HOST", "must be set in production")
+		}
+		if c.DBDriver == "memory" {
+			fail("DB_DRIVER", "memory loses every upload on restart and is not allowed in production")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// oneOf reports whether value is one of allowed
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrefix parses a CIDR range or a single IP address
+func parsePrefix(value string) (netip.Prefix, error) {
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(value)
+	return prefix.Masked(), err
+}
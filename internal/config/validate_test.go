@@ -0,0 +1,179 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns a development configuration that passes Validate
+func validConfig() *Config {
+	return &Config{
+		Port:              "8080",
+		Host:              "0.0.0.0",
+		Environment:       "development",
+		AdminUsername:     "admin",
+		AdminPassword:     "a-long-password",
+		JWTSecret:         "a-secret",
+		MaxFileSize:       1 << 20,
+		AllowedExtensions: []string{"pdf"},
+		StorageType:       "local",
+		StoragePath:       "./uploads",
+		DBDriver:          "sqlite",
+		SQLitePath:        "./data/test.db",
+		DBPort:            "5432",
+		DBName:            "neondb",
+		DBSSLMode:         "require",
+		EventBus:          "local",
+		ShutdownTimeout:   30 * time.Second,
+		LogLevel:          "info",
+	}
+}
+
+// wantErrors fails the test unless err mentions every one of want
+func wantErrors(t *testing.T, err error, want ...string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("error = nil, want %q", want)
+	}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("error %q does not mention %q", err, w)
+		}
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"sqlite", func(c *Config) {}},
+		{"memory", func(c *Config) { c.DBDriver = "memory" }},
+		{"postgres with the event bus", func(c *Config) { c.DBDriver = "postgres"; c.EventBus = "postgres" }},
+		{"limits disabled", func(c *Config) { c.RateLimitPerIP = 0; c.FolderMaxBytes = 0 }},
+		{"otlp endpoint", func(c *Config) { c.OTLPEndpoint = "http://localhost:4318" }},
+		{"production", func(c *Config) { c.Environment = "production"; c.DBDriver = "postgres"; c.DBHost = "db.example.com" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(c)
+			if err := c.Validate(); err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"port not a number", func(c *Config) { c.Port = "http" }, "PORT:"},
+		{"port out of range", func(c *Config) { c.Port = "70000" }, "PORT:"},
+		{"empty host", func(c *Config) { c.Host = " " }, "HOST:"},
+		{"unknown environment", func(c *Config) { c.Environment = "staging" }, "ENVIRONMENT:"},
+		{"empty admin username", func(c *Config) { c.AdminUsername = "" }, "ADMIN_USERNAME:"},
+		{"short admin password", func(c *Config) { c.AdminPassword = "short" }, "ADMIN_PASSWORD: must be at least 8"},
+		{"empty jwt secret", func(c *Config) { c.JWTSecret = "" }, "JWT_SECRET:"},
+		{"zero max file size", func(c *Config) { c.MaxFileSize = 0 }, "MAX_FILE_SIZE:"},
+		{"negative min free disk", func(c *Config) { c.MinFreeDisk = -1 }, "MIN_FREE_DISK:"},
+		{"no extensions", func(c *Config) { c.AllowedExtensions = nil }, "ALLOWED_EXTENSIONS: must list"},
+		{"invalid extension", func(c *Config) { c.AllowedExtensions = []string{"tar.gz"} }, `ALLOWED_EXTENSIONS: "tar.gz"`},
+		{"negative ip rate limit", func(c *Config) { c.RateLimitPerIP = -1 }, "RATE_LIMIT_PER_IP:"},
+		{"negative folder rate limit", func(c *Config) { c.RateLimitPerFolder = -1 }, "RATE_LIMIT_PER_FOLDER:"},
+		{"negative folder file quota", func(c *Config) { c.FolderMaxFiles = -1 }, "FOLDER_MAX_FILES:"},
+		{"negative folder byte quota", func(c *Config) { c.FolderMaxBytes = -1 }, "FOLDER_MAX_BYTES:"},
+		{"invalid trusted proxy", func(c *Config) { c.TrustedProxies = []string{"proxy.local"} }, "TRUSTED_PROXIES:"},
+		{"unsupported storage", func(c *Config) { c.StorageType = "s3" }, "STORAGE_TYPE:"},
+		{"empty storage path", func(c *Config) { c.StoragePath = "" }, "STORAGE_PATH:"},
+		{"invalid db port", func(c *Config) { c.DBDriver = "postgres"; c.DBPort = "0" }, "DB_PORT:"},
+		{"empty db name", func(c *Config) { c.DBDriver = "postgres"; c.DBName = "" }, "DB_NAME:"},
+		{"unknown ssl mode", func(c *Config) { c.DBDriver = "postgres"; c.DBSSLMode = "always" }, "DB_SSL_MODE:"},
+		{"empty sqlite path", func(c *Config) { c.SQLitePath = "" }, "SQLITE_PATH:"},
+		{"unknown db driver", func(c *Config) { c.DBDriver = "mysql" }, "DB_DRIVER:"},
+		{"unknown event bus", func(c *Config) { c.EventBus = "redis" }, "EVENT_BUS:"},
+		{"postgres bus without postgres", func(c *Config) { c.EventBus = "postgres" }, "EVENT_BUS: postgres requires DB_DRIVER=postgres"},
+		{"zero shutdown timeout", func(c *Config) { c.ShutdownTimeout = 0 }, "SHUTDOWN_TIMEOUT:"},
+		{"unknown log level", func(c *Config) { c.LogLevel = "verbose" }, "LOG_LEVEL:"},
+		{"otlp endpoint without scheme", func(c *Config) { c.OTLPEndpoint = "localhost:4318" }, "OTEL_EXPORTER_OTLP_ENDPOINT:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(c)
+			wantErrors(t, c.Validate(), tt.want)
+		})
+	}
+}
+
+func TestValidateReportsEveryError(t *testing.T) {
+	c := validConfig()
+	c.Port = "0"
+	c.JWTSecret = ""
+	c.AllowedExtensions = []string{"", "pdf"}
+	c.DBDriver = "mysql"
+	c.LogLevel = "loud"
+
+	err := c.Validate()
+	wantErrors(t, err, "PORT:", "JWT_SECRET:", "ALLOWED_EXTENSIONS:", "DB_DRIVER:", "LOG_LEVEL:")
+	if lines := strings.Count(err.Error(), "\n") + 1; lines != 5 {
+		t.Errorf("error reports %d problems, want 5:\n%v", lines, err)
+	}
+}
+
+func TestValidateProduction(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"default admin password", func(c *Config) { c.AdminPassword = defaultAdminPassword }, "ADMIN_PASSWORD: the default password"},
+		{"default jwt secret", func(c *Config) { c.JWTSecret = defaultJWTSecret }, "JWT_SECRET: the default secret"},
+		{"postgres without host", func(c *Config) { c.DBHost = "" }, "DB_HOST: must be set in production"},
+		{"memory database", func(c *Config) { c.DBDriver = "memory" }, "DB_DRIVER: memory loses every upload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			c.Environment = "production"
+			c.DBDriver = "postgres"
+			c.DBHost = "db.example.com"
+			tt.modify(c)
+			wantErrors(t, c.Validate(), tt.want)
+
+			// The same settings are accepted in development
+			c.Environment = "development"
+			if err := c.Validate(); err != nil {
+				t.Errorf("Validate() in development = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateNormalizes(t *testing.T) {
+	c := validConfig()
+	c.AllowedExtensions = []string{".PDF", " Png "}
+	c.TrustedProxies = []string{"10.0.0.7/8", "", "192.168.1.1", "2001:db8::1"}
+	c.LogLevel = " DEBUG "
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if want := []string{"pdf", "png"}; !slices.Equal(c.AllowedExtensions, want) {
+		t.Errorf("AllowedExtensions = %q, want %q", c.AllowedExtensions, want)
+	}
+	if want := []string{"10.0.0.0/8", "192.168.1.1/32", "2001:db8::1/128"}; !slices.Equal(c.TrustedProxies, want) {
+		t.Errorf("TrustedProxies = %q, want %q", c.TrustedProxies, want)
+	}
+	if c.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want debug", c.LogLevel)
+	}
+}
@@ -12,24 +12,45 @@ type UploadedFile struct {
 	FileType   string    `json:"file_type"`
 	FilePath   string    `json:"file_path"`
 	UploadedAt time.Time `json:"uploaded_at"`
+	PageCount  int       `json:"page_count"` // Printed pages: PDF page count, 1 for images
+	Text       string    `json:"-"`          // Extracted PDF text, indexed for search only
 }
 
 // Folder represents a collection of files
+// FileCount, TotalBytes and TotalPages are computed from the folder's files
+// whenever a folder is read; they are ignored when saving
 type Folder struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	FileCount int       `json:"file_count"`
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	FileCount  int       `json:"file_count"`
+	TotalBytes int64     `json:"total_bytes"`
+	TotalPages int       `json:"total_pages"`
+}
+
+// FolderWithFiles is a folder together with the files it contains
+type FolderWithFiles struct {
+	*Folder
+	Files []*UploadedFile `json:"files"`
+}
+
+// StorageReport compares file records with the uploads in storage
+type StorageReport struct {
+	Files        int             `json:"files"`         // File records checked
+	Blobs        int             `json:"blobs"`         // Uploads found in storage
+	MissingBlobs []*UploadedFile `json:"missing_blobs"` // Records whose upload is gone
+	OrphanBlobs  []string        `json:"orphan_blobs"`  // Uploads no record points to
+}
+
+// OK reports whether every record has its upload and every upload has its record
+func (r *StorageReport) OK() bool {
+	return len(r.MissingBlobs) == 0 && len(r.OrphanBlobs) == 0
 }
 
 // Admin represents an admin user
 type Admin struct {
 	Username     string
 	PasswordHash string
-}
-
-// WebSocketMessage represents a message sent via WebSocket
-type WebSocketMessage struct {
-	Type    string      `json:"type"` // "new_file", "file_deleted", "folder_created"
-	Payload interface{} `json:"payload"`
+	CreatedAt    time.Time // Set by the repository
+	Disabled     bool      // Disabled admins can't log in and their tokens are rejected
 }
@@ -0,0 +1,31 @@
+package domain
+
+import "errors"
+
+// Error kinds returned by repositories and services
+// Wrap them with context (fmt.Errorf("file %q: %w", id, ErrNotFound)) and
+// test with errors.Is; the HTTP layer maps each kind to a status code.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrValidation    = errors.New("validation failed")
+	ErrConflict      = errors.New("conflict")
+	ErrTooLarge      = errors.New("too large")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrUnavailable   = errors.New("unavailable")
+	ErrLimitExceeded = errors.New("limit exceeded")
+)
+
+// Error is a domain error with a client-safe message
+// errors.Is(err, Kind) holds, while Error() returns only Message
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Kind }
+
+// NewError creates an error of the given kind with a client-safe message
+func NewError(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
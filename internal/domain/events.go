@@ -0,0 +1,168 @@
+package domain
+
+import "time"
+
+//go:generate go run ../../cmd/eventschema -o ../../web/static/schema/events.v1.json
+
+// Event types sent over the WebSocket connection
+const (
+	EventHello           = "hello"            // First message on every connection
+	EventResyncRequired  = "resync_required"  // Missed events can't be replayed; refetch state
+	EventNewFile         = "new_file"         // A file was uploaded
+	EventFileDeleted     = "file_deleted"     // A file was deleted
+	EventFolderCreated   = "folder_created"   // A folder was created
+	EventFolderRenamed   = "folder_renamed"   // A folder was renamed
+	EventFolderDeleted   = "folder_deleted"   // A folder and all of its files were deleted
+	EventSettingsChanged = "settings_changed" // An admin saved new shop settings
+)
+
+// EventProtocolVersion is the newest WebSocket protocol version the server speaks
+// Bump it (and keep the old encoding available) when an event changes shape
+const EventProtocolVersion = 1
+
+// SupportedEventProtocolVersions lists every protocol version the server can speak
+var SupportedEventProtocolVersions = []int{1}
+
+// Event is a typed payload sent to WebSocket clients
+type Event interface {
+	EventType() string
+}
+
+// HelloEvent tells a client which protocol version was negotiated and where
+// the server's event sequence stands, so it can resume even if it disconnects
+// before receiving any event
+type HelloEvent struct {
+	Version   int    `json:"version"`   // Negotiated protocol version
+	Supported []int  `json:"supported"` // Every version the server speaks
+	Epoch     string `json:"epoch"`     // Identifies the sequence; it changes when the server restarts
+	Seq       uint64 `json:"seq"`       // Sequence number of the last event sent before this connection
+}
+
+// ResyncRequiredEvent tells a reconnecting client it missed too many events
+type ResyncRequiredEvent struct{}
+
+// FileEvent describes an uploaded file without server-side details such as its path
+type FileEvent struct {
+	ID         string    `json:"id"`
+	FolderID   string    `json:"folder_id"`
+	FolderName string    `json:"folder_name"`
+	FileName   string    `json:"file_name"`
+	FileSize   int64     `json:"file_size"`
+	FileType   string    `json:"file_type"`
+	PageCount  int       `json:"page_count"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// FileDeletedEvent identifies a deleted file
+type FileDeletedEvent struct {
+	ID       string `json:"id"`
+	FolderID string `json:"folder_id"`
+}
+
+// FolderCreatedEvent describes a newly created folder
+type FolderCreatedEvent struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	FileCount int       `json:"file_count"`
+}
+
+// FolderRenamedEvent carries a folder's new name
+type FolderRenamedEvent struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// FolderDeletedEvent identifies a deleted folder; its files are gone too
+type FolderDeletedEvent struct {
+	ID string `json:"id"`
+}
+
+// SettingsChangedEvent carries the settings now in effect
+// It is sent to admin dashboards only
+type SettingsChangedEvent struct {
+	Version   int64     `json:"version"`
+	Settings  Settings  `json:"settings"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (HelloEvent) EventType() string           { return EventHello }
+func (ResyncRequiredEvent) EventType() string  { return EventResyncRequired }
+func (FileEvent) EventType() string            { return EventNewFile }
+func (FileDeletedEvent) EventType() string     { return EventFileDeleted }
+func (FolderCreatedEvent) EventType() string   { return EventFolderCreated }
+func (FolderRenamedEvent) EventType() string   { return EventFolderRenamed }
+func (FolderDeletedEvent) EventType() string   { return EventFolderDeleted }
+func (SettingsChangedEvent) EventType() string { return EventSettingsChanged }
+
+// AllEvents returns a zero value of every event type, used to generate the schema
+func AllEvents() []Event {
+	return []Event{
+		HelloEvent{},
+		ResyncRequiredEvent{},
+		FileEvent{},
+		FileDeletedEvent{},
+		FolderCreatedEvent{},
+		FolderRenamedEvent{},
+		FolderDeletedEvent{},
+		SettingsChangedEvent{},
+	}
+}
+
+// NewFileEvent builds the new_file event for an uploaded file
+func NewFileEvent(file *UploadedFile) FileEvent {
+	return FileEvent{
+		ID:         file.ID,
+		FolderID:   file.FolderID,
+		FolderName: file.FolderName,
+		FileName:   file.FileName,
+		FileSize:   file.FileSize,
+		FileType:   file.FileType,
+		PageCount:  file.PageCount,
+		UploadedAt: file.UploadedAt,
+	}
+}
+
+// NewFileDeletedEvent builds the file_deleted event for a removed file
+func NewFileDeletedEvent(file *UploadedFile) FileDeletedEvent {
+	return FileDeletedEvent{
+		ID:       file.ID,
+		FolderID: file.FolderID,
+	}
+}
+
+// NewFolderCreatedEvent builds the folder_created event for a folder
+func NewFolderCreatedEvent(folder *Folder) FolderCreatedEvent {
+	return FolderCreatedEvent{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		CreatedAt: folder.CreatedAt,
+		FileCount: folder.FileCount,
+	}
+}
+
+// NewFolderRenamedEvent builds the folder_renamed event for a folder
+func NewFolderRenamedEvent(folder *Folder) FolderRenamedEvent {
+	return FolderRenamedEvent{
+		ID:   folder.ID,
+		Name: folder.Name,
+	}
+}
+
+// NewFolderDeletedEvent builds the folder_deleted event for a removed folder
+func NewFolderDeletedEvent(folder *Folder) FolderDeletedEvent {
+	return FolderDeletedEvent{
+		ID: folder.ID,
+	}
+}
+
+// NewSettingsChangedEvent builds the settings_changed event for a saved version
+func NewSettingsChangedEvent(v *SettingsVersion) SettingsChangedEvent {
+	return SettingsChangedEvent{
+		Version:   v.Version,
+		Settings:  v.Settings,
+		ChangedBy: v.ChangedBy,
+		ChangedAt: v.ChangedAt,
+	}
+}
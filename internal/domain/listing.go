@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page size limits for list queries
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// SortField is a column list queries can be ordered by
+type SortField string
+
+const (
+	SortByDate SortField = "date" // uploaded_at for files, created_at for folders
+	SortByName SortField = "name" // file_name for files, name for folders
+	SortBySize SortField = "size" // file_size (files only)
+)
+
+// Sort orders a list query; ties are broken by ID in the same direction
+type Sort struct {
+	Field     SortField
+	Ascending bool
+}
+
+// DefaultSort lists newest items first
+var DefaultSort = Sort{Field: SortByDate}
+
+// ParseSort parses "field" (ascending) or "-field" (descending)
+// An empty string yields DefaultSort
+func ParseSort(s string, allowed ...SortField) (Sort, error) {
+	if s == "" {
+		return DefaultSort, nil
+	}
+
+	sort := Sort{Ascending: true}
+	if strings.HasPrefix(s, "-") {
+		sort.Ascending = false
+		s = s[1:]
+	}
+	sort.Field = SortField(s)
+
+	for _, field := range allowed {
+		if sort.Field == field {
+			return sort, nil
+		}
+	}
+	return Sort{}, NewError(ErrValidation, "unsupported sort field "+strconv.Quote(s))
+}
+
+// String formats the sort the way ParseSort accepts it
+func (s Sort) String() string {
+	if s.Ascending {
+		return string(s.Field)
+	}
+	return "-" + string(s.Field)
+}
+
+// FileQuery selects a page of uploaded files
+// Zero-valued filters are ignored. Files have no status yet, so there is no
+// status filter.
+type FileQuery struct {
+	FolderID string    // Only files in this folder
+	FileType string    // Only files with this extension (e.g., "pdf")
+	From     time.Time // Uploaded at or after
+	To       time.Time // Uploaded before
+	Search   string    // Case-insensitive substring of the file or folder name
+	Sort     Sort      // Defaults to newest first
+	Cursor   string    // NextCursor of the previous page
+	Limit    int       // Page size, clamped to MaxPageSize
+}
+
+// FilePage is one page of files
+type FilePage struct {
+	Files      []*UploadedFile `json:"files"`
+	NextCursor string          `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// FolderQuery selects a page of folders
+// Zero-valued filters are ignored
+type FolderQuery struct {
+	From   time.Time // Created at or after
+	To     time.Time // Created before
+	Search string    // Case-insensitive substring of the folder name
+	Sort   Sort      // Defaults to newest first (size is not supported)
+	Cursor string    // NextCursor of the previous page
+	Limit  int       // Page size, clamped to MaxPageSize
+}
+
+// FolderPage is one page of folders
+type FolderPage struct {
+	Folders    []*Folder `json:"folders"`
+	NextCursor string    `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// PageSize clamps a requested limit to [1, MaxPageSize], defaulting to DefaultPageSize
+func PageSize(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageSize
+	case limit > MaxPageSize:
+		return MaxPageSize
+	default:
+		return limit
+	}
+}
+
+// Cursor is the decoded position after the last item of a page: the sort
+// key and ID of that item, and the sort it was produced with
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// EncodeCursor builds an opaque cursor string
+func EncodeCursor(sort Sort, value, id string) string {
+	data, _ := json.Marshal(Cursor{Sort: sort.String(), Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor for the same sort
+func DecodeCursor(s string, sort Sort) (*Cursor, error) {
+	invalid := NewError(ErrValidation, "invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sort.String() {
+		return nil, invalid
+	}
+	return &c, nil
+}
+
+// FileSortValue returns the cursor value of a file for the given sort field
+func FileSortValue(f *UploadedFile, field SortField) string {
+	switch field {
+	case SortByName:
+		return f.FileName
+	case SortBySize:
+		return strconv.FormatInt(f.FileSize, 10)
+	default:
+		return f.UploadedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// FolderSortValue returns the cursor value of a folder for the given sort field
+func FolderSortValue(f *Folder, field SortField) string {
+	if field == SortByName {
+		return f.Name
+	}
+	return f.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// CursorArg converts a cursor value back to the type stored in the sort column
+func CursorArg(c *Cursor, field SortField) (interface{}, error) {
+	switch field {
+	case SortByName:
+		return c.Value, nil
+	case SortBySize:
+		size, err := strconv.ParseInt(c.Value, 10, 64)
+		if err != nil {
+			return nil, NewError(ErrValidation, "invalid cursor")
+		}
+		return size, nil
+	default:
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, NewError(ErrValidation, "invalid cursor")
+		}
+		return t, nil
+	}
+}
@@ -1,23 +1,55 @@
 package domain
 
+import "context"
+
 // FileRepository defines the interface for file storage operations
 type FileRepository interface {
-	SaveFile(file *UploadedFile) error
-	GetFile(id string) (*UploadedFile, error)
-	GetFilesByFolder(folderID string) ([]*UploadedFile, error)
-	GetAllFiles() ([]*UploadedFile, error)
-	DeleteFile(id string) error
+	SaveFile(ctx context.Context, file *UploadedFile) error
+	GetFile(ctx context.Context, id string) (*UploadedFile, error)
+	GetFilesByFolder(ctx context.Context, folderID string) ([]*UploadedFile, error)
+	GetFilesByFolders(ctx context.Context, folderIDs []string) (map[string][]*UploadedFile, error) // Keyed by folder ID, newest first; folders without files are left out
+	GetAllFiles(ctx context.Context) ([]*UploadedFile, error)
+	ListFiles(ctx context.Context, query FileQuery) (*FilePage, error)
+	DeleteFile(ctx context.Context, id string) error
+	UpdateFolderName(ctx context.Context, folderID, name string) error
 }
 
 // FolderRepository defines the interface for folder operations
+// Folders are returned with FileCount, TotalBytes and TotalPages derived from their files
 type FolderRepository interface {
-	CreateFolder(folder *Folder) error
-	GetFolder(id string) (*Folder, error)
-	GetAllFolders() ([]*Folder, error)
-	UpdateFolderFileCount(folderID string, count int) error
+	CreateFolder(ctx context.Context, folder *Folder) error
+	GetFolder(ctx context.Context, id string) (*Folder, error)
+	GetAllFolders(ctx context.Context) ([]*Folder, error)
+	ListFolders(ctx context.Context, query FolderQuery) (*FolderPage, error)
+	RenameFolder(ctx context.Context, id, name string) error
+	DeleteFolder(ctx context.Context, id string) error
 }
 
 // AdminRepository defines the interface for admin operations
 type AdminRepository interface {
-	GetAdminByUsername(username string) (*Admin, error)
+	GetAdminByUsername(ctx context.Context, username string) (*Admin, error)
+	CreateAdmin(ctx context.Context, admin *Admin) error                     // ErrConflict if the username is taken
+	UpdatePassword(ctx context.Context, username, passwordHash string) error // ErrNotFound if there is no such admin
+	SetDisabled(ctx context.Context, username string, disabled bool) error   // ErrNotFound if there is no such admin
+	ListAdmins(ctx context.Context) ([]*Admin, error)                        // Ordered by username
+}
+
+// SettingsRepository stores every version of the shop settings
+type SettingsRepository interface {
+	GetSettings(ctx context.Context) (*SettingsVersion, error)                      // Newest version; ErrNotFound if none was saved
+	SaveSettings(ctx context.Context, version *SettingsVersion) error               // ErrConflict if the version number is taken
+	ListSettingsHistory(ctx context.Context, limit int) ([]*SettingsVersion, error) // Newest first
+}
+
+// Repositories groups the repositories that take part in a transaction
+type Repositories struct {
+	Files   FileRepository
+	Folders FolderRepository
+}
+
+// UnitOfWork runs a set of repository operations atomically
+// The repositories passed to fn share one transaction: it is committed if fn
+// returns nil and rolled back otherwise.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
 }
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kinds of search hits
+const (
+	SearchKindFolder = "folder"
+	SearchKindFile   = "file"
+)
+
+// Search result limits
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+)
+
+// SearchHit is one ranked search result
+type SearchHit struct {
+	Kind       string  `json:"kind"`                // SearchKindFolder or SearchKindFile
+	ID         string  `json:"id"`                  // Folder or file ID
+	FolderID   string  `json:"folder_id"`           // Same as ID for folders
+	FolderName string  `json:"folder_name"`         // Name of the (parent) folder
+	FileName   string  `json:"file_name,omitempty"` // Files only
+	Rank       float64 `json:"rank"`                // Higher is more relevant
+}
+
+// SearchRepository runs full-text search over folders and files
+// Every term must match (as a word prefix) the folder name, file name or
+// extracted PDF text; hits are returned best first
+type SearchRepository interface {
+	Search(ctx context.Context, terms []string, limit int) ([]*SearchHit, error)
+}
+
+// SearchTerms splits a query into lowercase words, dropping punctuation
+// "John's CV.pdf" yields [john s cv pdf]
+func SearchTerms(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// RankField is a piece of text searched with a relative weight
+type RankField struct {
+	Text   string
+	Weight float64
+}
+
+// Rank scores text for search terms the way the SQL-less repositories do
+// It returns 0 unless every term prefixes some word of some field; otherwise
+// each term adds the weight of the best field it matches
+func Rank(terms []string, fields ...RankField) float64 {
+	words := make([][]string, len(fields))
+	for i, field := range fields {
+		words[i] = SearchTerms(field.Text)
+	}
+
+	score := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for i, field := range fields {
+			if field.Weight > best && hasPrefixWord(words[i], term) {
+				best = field.Weight
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		score += best
+	}
+	return score
+}
+
+func hasPrefixWord(words []string, term string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// SortSearchHits orders hits best first, then by name for stable results
+func SortSearchHits(hits []*SearchHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		if a.FolderName != b.FolderName {
+			return a.FolderName < b.FolderName
+		}
+		return a.FileName < b.FileName
+	})
+}
+
+// Weights used by Rank for each searched field
+const (
+	RankWeightName    = 1.0 // File name, or folder name of a folder hit
+	RankWeightFolder  = 0.8 // Folder name of a file hit
+	RankWeightContent = 0.4 // Extracted PDF text
+)
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Settings are the shop settings admins can change while the server runs
+type Settings struct {
+	ShopName          string         `json:"shop_name"`
+	MaxFileSize       int64          `json:"max_file_size"`      // Largest accepted upload, in bytes
+	AllowedExtensions []string       `json:"allowed_extensions"` // Lower case, without the dot
+	Currency          string         `json:"currency"`           // ISO 4217 code for the price list, e.g. EUR
+	PriceList         []Price        `json:"price_list"`
+	OpeningHours      []OpeningHours `json:"opening_hours"`
+}
+
+// Price is one line of the price list
+type Price struct {
+	Item  string `json:"item"`  // e.g. "A4 black & white, per page"
+	Cents int64  `json:"cents"` // In the currency's minor unit
+}
+
+// OpeningHours are the hours the shop is open on one day of the week
+// A day may appear more than once, e.g. for a lunch break
+type OpeningHours struct {
+	Day   string `json:"day"`   // monday ... sunday
+	Open  string `json:"open"`  // HH:MM, 24-hour clock
+	Close string `json:"close"` // HH:MM, later than Open
+}
+
+// SettingsVersion is one saved revision of the settings
+// Every change is kept: the newest version is in effect and the older ones
+// form the change history. Version 0 is the configured defaults, in effect
+// until settings are first saved.
+type SettingsVersion struct {
+	Version   int64     `json:"version"`
+	Settings  Settings  `json:"settings"`
+	ChangedBy string    `json:"changed_by,omitempty"` // Admin username
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// MaxPriceListItems and MaxOpeningHours bound the lists an admin can save
+const (
+	MaxPriceListItems = 100
+	MaxOpeningHours   = 21
+)
+
+var weekdays = map[string]bool{
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true,
+	"friday": true, "saturday": true, "sunday": true,
+}
+
+// Validate checks the settings, returning an ErrValidation error for the first
+// problem found
+// Names are trimmed, extensions lower-cased without their dot and days
+// lower-cased, and nil lists become empty so they encode as [].
+func (s *Settings) Validate() error {
+	s.ShopName = strings.TrimSpace(s.ShopName)
+	if s.ShopName == "" {
+		return NewError(ErrValidation, "shop_name is required")
+	}
+	if s.MaxFileSize <= 0 {
+		return NewError(ErrValidation, "max_file_size must be a positive number of bytes")
+	}
+
+	if len(s.AllowedExtensions) == 0 {
+		return NewError(ErrValidation, "allowed_extensions must list at least one extension")
+	}
+	for i, ext := range s.AllowedExtensions {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext == "" || strings.ContainsAny(ext, "./\\ ") {
+			return NewError(ErrValidation, fmt.Sprintf("allowed_extensions: %q is not a file extension", s.AllowedExtensions[i]))
+		}
+		s.AllowedExtensions[i] = ext
+	}
+
+	s.Currency = strings.ToUpper(strings.TrimSpace(s.Currency))
+	if s.Currency != "" && !isCurrencyCode(s.Currency) {
+		return NewError(ErrValidation, "currency must be a three-letter ISO 4217 code")
+	}
+
+	if s.PriceList == nil {
+		s.PriceList = make([]Price, 0)
+	}
+	if len(s.PriceList) > MaxPriceListItems {
+		return NewError(ErrValidation, fmt.Sprintf("price_list can have at most %d items", MaxPriceListItems))
+	}
+	for i := range s.PriceList {
+		price := &s.PriceList[i]
+		price.Item = strings.TrimSpace(price.Item)
+		if price.Item == "" {
+			return NewError(ErrValidation, "price_list: every item needs a name")
+		}
+		if price.Cents < 0 {
+			return NewError(ErrValidation, fmt.Sprintf("price_list: %q has a negative price", price.Item))
+		}
+	}
+	if len(s.PriceList) > 0 && s.Currency == "" {
+		return NewError(ErrValidation, "currency is required when there is a price list")
+	}
+
+	if s.OpeningHours == nil {
+		s.OpeningHours = make([]OpeningHours, 0)
+	}
+	if len(s.OpeningHours) > MaxOpeningHours {
+		return NewError(ErrValidation, fmt.Sprintf("opening_hours can have at most %d entries", MaxOpeningHours))
+	}
+	for i := range s.OpeningHours {
+		hours := &s.OpeningHours[i]
+		hours.Day = strings.ToLower(strings.TrimSpace(hours.Day))
+		if !weekdays[hours.Day] {
+			return NewError(ErrValidation, fmt.Sprintf("opening_hours: %q is not a day of the week", hours.Day))
+		}
+		open, err := time.Parse("15:04", hours.Open)
+		if err != nil {
+			return NewError(ErrValidation, fmt.Sprintf("opening_hours: %s opens at %q, expected HH:MM", hours.Day, hours.Open))
+		}
+		closing, err := time.Parse("15:04", hours.Close)
+		if err != nil {
+			return NewError(ErrValidation, fmt.Sprintf("opening_hours: %s closes at %q, expected HH:MM", hours.Day, hours.Close))
+		}
+		if !closing.After(open) {
+			return NewError(ErrValidation, fmt.Sprintf("opening_hours: %s must close after it opens", hours.Day))
+		}
+	}
+	return nil
+}
+
+// AllowsExtension reports whether uploads with ext (lower case, no dot) are accepted
+func (s *Settings) AllowsExtension(ext string) bool {
+	for _, allowed := range s.AllowedExtensions {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
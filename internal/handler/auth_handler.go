@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"fileprintapp/internal/problem"
 	"fileprintapp/internal/usecase"
 	"net/http"
 )
@@ -26,13 +27,13 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
-	token, err := h.authService.Login(req.Username, req.Password)
+	token, err := h.authService.Login(r.Context(), req.Username, req.Password)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		problem.Error(w, r, err)
 		return
 	}
 
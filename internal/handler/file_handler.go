@@ -2,78 +2,213 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/metrics"
+	"fileprintapp/internal/problem"
+	"fileprintapp/internal/ratelimit"
 	"fileprintapp/internal/usecase"
 	ws "fileprintapp/internal/websocket"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
+const (
+	// uploadFormOverhead is how much of an upload request may go to anything
+	// but the file itself: the folder fields and the part headers
+	uploadFormOverhead = 64 << 10
+
+	// maxUploadField is the longest form field accepted before the file part
+	maxUploadField = 1 << 10
+)
+
 // FileHandler handles file-related endpoints
 type FileHandler struct {
 	fileService   *usecase.FileService
 	folderService *usecase.FolderService
 	hub           *ws.Hub
+	folderLimiter *ratelimit.Limiter // Uploads per folder; nil for no limit
 }
 
 // NewFileHandler creates a new file handler
-func NewFileHandler(fileService *usecase.FileService, folderService *usecase.FolderService, hub *ws.Hub) *FileHandler {
+func NewFileHandler(fileService *usecase.FileService, folderService *usecase.FolderService, hub *ws.Hub, folderLimiter *ratelimit.Limiter) *FileHandler {
 	return &FileHandler{
 		fileService:   fileService,
 		folderService: folderService,
 		hub:           hub,
+		folderLimiter: folderLimiter,
 	}
 }
 
 // UploadFile handles file upload
+// The form is read as a stream: folder_id and folder_name must come before
+// the file part, so the per-folder rate limit is checked before the file is
+// read, and the file goes to disk as it arrives.
 func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
-	// Parse multipart form
-	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10 MB
-		http.Error(w, "Unable to parse form", http.StatusBadRequest)
+	start := time.Now()
+
+	// Refuse new uploads during shutdown; ones already started are waited for
+	done, err := h.fileService.BeginUpload()
+	if err != nil {
+		metrics.UploadsRejected.WithLabelValues(metrics.RejectReason(err)).Inc()
+		problem.Error(w, r, err)
+		return
+	}
+	defer done()
+	metrics.UploadsInFlight.Inc()
+	defer metrics.UploadsInFlight.Dec()
+
+	// Never read more than the largest accepted file and its form fields
+	maxFileSize, err := h.fileService.MaxUploadSize(r.Context())
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+uploadFormOverhead)
+
+	form, err := r.MultipartReader()
+	if err != nil {
+		metrics.UploadsRejected.WithLabelValues(metrics.RejectBadRequest).Inc()
+		problem.Write(w, r, http.StatusBadRequest, "Unable to parse form")
+		return
+	}
+	fields, file, err := readUploadForm(form)
+	if err != nil {
+		if err = uploadError(err); errors.As(err, new(*domain.Error)) {
+			metrics.UploadsRejected.WithLabelValues(metrics.RejectReason(err)).Inc()
+			problem.Error(w, r, err)
+			return
+		}
+		metrics.UploadsRejected.WithLabelValues(metrics.RejectBadRequest).Inc()
+		problem.Write(w, r, http.StatusBadRequest, "Unable to parse form")
 		return
 	}
+	if file != nil {
+		defer file.Close()
+	}
 
-	folderID := r.FormValue("folder_id")
-	folderName := r.FormValue("folder_name")
+	folderID := fields["folder_id"]
+	folderName := fields["folder_name"]
 
 	if folderID == "" || folderName == "" {
-		http.Error(w, "Folder ID and name are required", http.StatusBadRequest)
+		metrics.UploadsRejected.WithLabelValues(metrics.RejectBadRequest).Inc()
+		problem.Write(w, r, http.StatusBadRequest, "Folder ID and name are required and must come before the file")
 		return
 	}
 
-	// Get file from form
-	file, handler, err := r.FormFile("file")
-	if err != nil {
-		http.Error(w, "Unable to get file", http.StatusBadRequest)
+	// Stops a flood of uploads into one folder, even from many addresses
+	if ok, retryAfter := h.folderLimiter.Allow(folderID); !ok {
+		metrics.UploadsRejected.WithLabelValues(metrics.RejectRateLimited).Inc()
+		problem.TooManyRequests(w, r, retryAfter, "Too many uploads to this folder, please retry shortly")
+		return
+	}
+
+	if file == nil || file.FileName() == "" {
+		metrics.UploadsRejected.WithLabelValues(metrics.RejectBadRequest).Inc()
+		problem.Write(w, r, http.StatusBadRequest, "Unable to get file")
 		return
 	}
-	file.Close()
 
 	// Upload file
-	uploadedFile, err := h.fileService.UploadFile(handler, folderID, folderName)
+	uploadedFile, err := h.fileService.UploadFile(r.Context(), folderID, folderName, file.FileName(), file)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		err = uploadError(err)
+		metrics.UploadsRejected.WithLabelValues(metrics.RejectReason(err)).Inc()
+		problem.Error(w, r, err)
 		return
 	}
+	metrics.UploadBytes.Observe(float64(uploadedFile.FileSize))
+	metrics.UploadDuration.Observe(time.Since(start).Seconds())
 
-	// Broadcast to WebSocket clients
-	h.hub.BroadcastMessage("new_file", uploadedFile)
+	// Broadcast to admins and to the customer watching this folder
+	h.hub.BroadcastToTopic(r.Context(), ws.FolderTopic(folderID), domain.NewFileEvent(uploadedFile))
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(uploadedFile)
 }
 
-// GetAllFiles retrieves all files
-func (h *FileHandler) GetAllFiles(w http.ResponseWriter, r *http.Request) {
-	files, err := h.fileService.GetAllFiles()
+// readUploadForm reads the form fields up to the file part
+// It returns the fields and the file part, which is nil if the form has none
+// and must be closed otherwise.
+func readUploadForm(form *multipart.Reader) (map[string]string, *multipart.Part, error) {
+	fields := make(map[string]string)
+	for {
+		part, err := form.NextPart()
+		if err == io.EOF {
+			return fields, nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if part.FormName() == "file" {
+			return fields, part, nil
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxUploadField+1))
+		part.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(value) > maxUploadField {
+			return nil, nil, domain.NewError(domain.ErrValidation, "form field "+part.FormName()+" is too long")
+		}
+		fields[part.FormName()] = string(value)
+	}
+}
+
+// uploadError reports a body cut off by the MaxBytesReader as too large
+func uploadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return domain.NewError(domain.ErrTooLarge, "file size exceeds maximum allowed size")
+	}
+	return err
+}
+
+// ListFiles retrieves one page of files
+// Query parameters: folder_id, type, from, to, q, sort, cursor, limit
+func (h *FileHandler) ListFiles(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	order, err := domain.ParseSort(params.Get("sort"), domain.SortByDate, domain.SortByName, domain.SortBySize)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	from, to, err := parseDateRange(params)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	limit, err := parseLimit(params)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	page, err := h.fileService.ListFiles(r.Context(), domain.FileQuery{
+		FolderID: params.Get("folder_id"),
+		FileType: strings.TrimPrefix(strings.ToLower(params.Get("type")), "."),
+		From:     from,
+		To:       to,
+		Search:   params.Get("q"),
+		Sort:     order,
+		Cursor:   params.Get("cursor"),
+		Limit:    limit,
+	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Error(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(files)
+	json.NewEncoder(w).Encode(page)
 }
 
 // DeleteFile deletes a file
@@ -81,13 +216,14 @@ func (h *FileHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	fileID := vars["id"]
 
-	if err := h.fileService.DeleteFile(fileID); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	file, err := h.fileService.DeleteFile(r.Context(), fileID)
+	if err != nil {
+		problem.Error(w, r, err)
 		return
 	}
 
-	// Broadcast to WebSocket clients
-	h.hub.BroadcastMessage("file_deleted", map[string]string{"id": fileID})
+	// Broadcast to admins and to the customer watching this folder
+	h.hub.BroadcastToTopic(r.Context(), ws.FolderTopic(file.FolderID), domain.NewFileDeletedEvent(file))
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -97,9 +233,9 @@ func (h *FileHandler) ViewFile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	fileID := vars["id"]
 
-	file, err := h.fileService.GetFile(fileID)
+	file, err := h.fileService.GetFile(r.Context(), fileID)
 	if err != nil {
-		http.Error(w, "File not found", http.StatusNotFound)
+		problem.Error(w, r, err)
 		return
 	}
 
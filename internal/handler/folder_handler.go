@@ -2,9 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/problem"
 	"fileprintapp/internal/usecase"
 	ws "fileprintapp/internal/websocket"
 	"net/http"
+
+	"github.com/gorilla/mux"
 )
 
 // FolderHandler handles folder-related endpoints
@@ -28,36 +32,126 @@ func (h *FolderHandler) CreateFolder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
 	if req.Name == "" {
-		http.Error(w, "Folder name is required", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, "Folder name is required")
+		return
+	}
+
+	folder, err := h.folderService.CreateFolder(r.Context(), req.Name)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	// Broadcast to admins and to the customer watching this folder
+	h.hub.BroadcastToTopic(r.Context(), ws.FolderTopic(folder.ID), domain.NewFolderCreatedEvent(folder))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(folder)
+}
+
+// ListFolders retrieves one page of folders
+// Query parameters: from, to, q, sort, cursor, limit, include=files
+func (h *FolderHandler) ListFolders(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	order, err := domain.ParseSort(params.Get("sort"), domain.SortByDate, domain.SortByName)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	from, to, err := parseDateRange(params)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	limit, err := parseLimit(params)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	page, err := h.folderService.ListFolders(r.Context(), domain.FolderQuery{
+		From:   from,
+		To:     to,
+		Search: params.Get("q"),
+		Sort:   order,
+		Cursor: params.Get("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		problem.Error(w, r, err)
 		return
 	}
 
-	folder, err := h.folderService.CreateFolder(req.Name)
+	w.Header().Set("Content-Type", "application/json")
+
+	// ?include=files nests each folder's files in the response
+	if params.Get("include") != "files" {
+		json.NewEncoder(w).Encode(page)
+		return
+	}
+	folders, err := h.folderService.WithFiles(r.Context(), page.Folders)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Error(w, r, err)
 		return
 	}
+	json.NewEncoder(w).Encode(struct {
+		Folders    []*domain.FolderWithFiles `json:"folders"`
+		NextCursor string                    `json:"next_cursor,omitempty"`
+	}{folders, page.NextCursor})
+}
 
-	// Broadcast to WebSocket clients
-	h.hub.BroadcastMessage("folder_created", folder)
+// GetFolder retrieves a folder with its files
+func (h *FolderHandler) GetFolder(w http.ResponseWriter, r *http.Request) {
+	folder, err := h.folderService.GetFolderWithFiles(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(folder)
 }
 
-// GetAllFolders retrieves all folders
-func (h *FolderHandler) GetAllFolders(w http.ResponseWriter, r *http.Request) {
-	folders, err := h.folderService.GetAllFolders()
+// RenameFolder changes a folder's name
+func (h *FolderHandler) RenameFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	folder, err := h.folderService.RenameFolder(r.Context(), mux.Vars(r)["id"], req.Name)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Error(w, r, err)
 		return
 	}
 
+	// Broadcast to admins and to the customer watching this folder
+	h.hub.BroadcastToTopic(r.Context(), ws.FolderTopic(folder.ID), domain.NewFolderRenamedEvent(folder))
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(folders)
+	json.NewEncoder(w).Encode(folder)
+}
+
+// DeleteFolder deletes a folder with all of its files
+func (h *FolderHandler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	folder, err := h.folderService.DeleteFolder(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	// Broadcast to admins and to the customer watching this folder
+	h.hub.BroadcastToTopic(r.Context(), ws.FolderTopic(folder.ID), domain.NewFolderDeletedEvent(folder))
+
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"fileprintapp/internal/health"
+	"fileprintapp/internal/problem"
+	"log/slog"
+	"net/http"
+)
+
+// HealthHandler handles health probes and the admin diagnostics report
+type HealthHandler struct {
+	healthService *health.Service
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(healthService *health.Service) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// probeCheck is a check as shown to anonymous callers: no error details
+type probeCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// Live reports that the process is up and serving requests
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]string{"status": health.StatusOK})
+}
+
+// Ready reports whether the server can take traffic, with 503 if any check fails
+// Failure details are logged rather than returned, since the route is public
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ready, results := h.healthService.Ready(r.Context())
+
+	checks := make([]probeCheck, 0, len(results))
+	for _, result := range results {
+		if result.Status != health.StatusOK {
+			slog.WarnContext(r.Context(), "readiness check failed", "check", result.Name, "error", result.Error)
+		}
+		checks = append(checks, probeCheck{Name: result.Name, Status: result.Status})
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": status, "checks": checks})
+}
+
+// Diagnostics returns version, uptime, configuration, storage and migration details
+func (h *HealthHandler) Diagnostics(w http.ResponseWriter, r *http.Request) {
+	report, err := h.healthService.Diagnostics(r.Context())
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fileprintapp/internal/domain"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// dateOnly is the YYYY-MM-DD form accepted for from/to
+const dateOnly = "2006-01-02"
+
+// parseDateRange reads the from and to query parameters
+// Both accept RFC 3339 timestamps or YYYY-MM-DD dates; a date in "to"
+// includes that whole day
+func parseDateRange(params url.Values) (from, to time.Time, err error) {
+	if from, err = parseTime(params.Get("from"), "from", false); err != nil {
+		return
+	}
+	to, err = parseTime(params.Get("to"), "to", true)
+	return
+}
+
+func parseTime(s, name string, endOfDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(dateOnly, s)
+	if err != nil {
+		return time.Time{}, domain.NewError(domain.ErrValidation, name+" must be an RFC 3339 timestamp or YYYY-MM-DD date")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// parseLimit reads the limit query parameter (0 when absent)
+func parseLimit(params url.Values) (int, error) {
+	s := params.Get("limit")
+	if s == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 {
+		return 0, domain.NewError(domain.ErrValidation, "limit must be a positive integer")
+	}
+	return limit, nil
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/json"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/problem"
+	"fileprintapp/internal/usecase"
+	"net/http"
+)
+
+// SearchHandler handles search endpoints
+type SearchHandler struct {
+	searchService *usecase.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchService *usecase.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search returns ranked folder and file hits
+// Query parameters: q, limit
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	limit, err := parseLimit(params)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	hits, err := h.searchService.Search(r.Context(), params.Get("q"), limit)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Hits []*domain.SearchHit `json:"hits"`
+	}{hits})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/middleware"
+	"fileprintapp/internal/problem"
+	"fileprintapp/internal/usecase"
+	ws "fileprintapp/internal/websocket"
+	"net/http"
+)
+
+// SettingsHandler handles the shop settings endpoints
+type SettingsHandler struct {
+	settingsService *usecase.SettingsService
+	hub             *ws.Hub
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(settingsService *usecase.SettingsService, hub *ws.Hub) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: settingsService,
+		hub:             hub,
+	}
+}
+
+// GetSettings returns the settings in effect with their version
+func (h *SettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	current, err := h.settingsService.Current(r.Context())
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(current)
+}
+
+// UpdateSettings saves new settings
+// The body is {"version": n, "settings": {...}}, where version is the one
+// returned by GetSettings; a stale version is rejected with 409 Conflict
+func (h *SettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Version  *int64           `json:"version"`
+		Settings *domain.Settings `json:"settings"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if req.Version == nil || req.Settings == nil {
+		problem.Write(w, r, http.StatusBadRequest, "version and settings are required")
+		return
+	}
+
+	username, _ := r.Context().Value(middleware.UsernameKey).(string)
+	saved, err := h.settingsService.Update(r.Context(), *req.Settings, *req.Version, username)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	// Broadcast to admin dashboards
+	h.hub.BroadcastMessage(r.Context(), domain.NewSettingsChangedEvent(saved))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// ListSettingsHistory returns saved settings versions, newest first
+// Query parameters: limit
+func (h *SettingsHandler) ListSettingsHistory(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r.URL.Query())
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	versions, err := h.settingsService.History(r.Context(), limit)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Versions []*domain.SettingsVersion `json:"versions"`
+	}{versions})
+}
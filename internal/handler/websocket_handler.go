@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"fileprintapp/internal/logging"
+	"fileprintapp/internal/problem"
+	"fileprintapp/internal/usecase"
 	ws "fileprintapp/internal/websocket"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,26 +23,94 @@ var upgrader = websocket.Upgrader{
 
 // WebSocketHandler handles WebSocket connections
 type WebSocketHandler struct {
-	hub *ws.Hub
+	hub           *ws.Hub
+	authService   *usecase.AuthService
+	folderService *usecase.FolderService
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
-func NewWebSocketHandler(hub *ws.Hub) *WebSocketHandler {
+func NewWebSocketHandler(hub *ws.Hub, authService *usecase.AuthService, folderService *usecase.FolderService) *WebSocketHandler {
 	return &WebSocketHandler{
-		hub: hub,
+		hub:           hub,
+		authService:   authService,
+		folderService: folderService,
 	}
 }
 
 // HandleWebSocket upgrades HTTP connection to WebSocket
+// Admins connect with ?token=<jwt> and receive every event.
+// Customers connect with ?folder=<folder id> and only receive events for that folder.
+// Reconnecting clients may pass ?epoch=<epoch>&last_seen_seq=<n>, taken from
+// the hello message and the events they saw, to receive the events they missed;
+// a resume without the epoch always gets a resync.
+// Clients may list the protocol versions they speak with ?v=1,2; the server
+// picks the newest common one and announces it in the first (hello) message.
 func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	admin := false
+	var topics []string
+
+	if token := r.URL.Query().Get("token"); token != "" {
+		if _, err := h.authService.ValidateToken(r.Context(), token); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		admin = true
+	} else if folderID := r.URL.Query().Get("folder"); folderID != "" {
+		if _, err := h.folderService.GetFolder(r.Context(), folderID); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		topics = append(topics, ws.FolderTopic(folderID))
+	} else {
+		problem.Write(w, r, http.StatusUnauthorized, "token or folder is required")
+		return
+	}
+
+	var requested []int
+	if raw := r.URL.Query().Get("v"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			v, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, "Invalid protocol version")
+				return
+			}
+			requested = append(requested, v)
+		}
+	}
+	version, ok := ws.NegotiateVersion(requested)
+	if !ok {
+		problem.Write(w, r, http.StatusBadRequest, "No supported protocol version")
+		return
+	}
+
+	var lastSeenSeq uint64
+	epoch := r.URL.Query().Get("epoch")
+	resume := false
+	if raw := r.URL.Query().Get("last_seen_seq"); raw != "" {
+		seq, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, "Invalid last_seen_seq")
+			return
+		}
+		lastSeenSeq = seq
+		resume = true
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+		slog.WarnContext(r.Context(), "websocket upgrade failed", "error", err)
 		return
 	}
 
-	client := ws.NewClient(h.hub, conn)
-	h.hub.Register <- client
+	client := ws.NewClient(h.hub, conn, version, admin, topics...)
+	client.SetRequestID(logging.RequestID(r.Context()))
+	if resume {
+		client.ResumeFrom(epoch, lastSeenSeq)
+	}
+	if !h.hub.Register(client) {
+		conn.Close()
+		return
+	}
 
 	go client.WritePump()
 	go client.ReadPump()
@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DatabaseCheck pings the database; a nil db (the in-memory backend) always passes
+func DatabaseCheck(db *sql.DB) Check {
+	return Check{Name: "database", Run: func(ctx context.Context) error {
+		if db == nil {
+			return nil
+		}
+		return db.PingContext(ctx)
+	}}
+}
+
+// StorageCheck creates and removes a file in dir to prove uploads can be written
+func StorageCheck(dir string) Check {
+	return Check{Name: "storage", Run: func(ctx context.Context) error {
+		f, err := os.CreateTemp(dir, ".readyz-*")
+		if err != nil {
+			return err
+		}
+		_, err = f.Write([]byte("ok"))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if removeErr := os.Remove(f.Name()); err == nil {
+			err = removeErr
+		}
+		return err
+	}}
+}
+
+// DiskCheck fails when the disk holding dir has fewer than minFree bytes free
+// A minFree of 0, or a platform where free space can't be read, always passes
+func DiskCheck(dir string, minFree int64) Check {
+	return Check{Name: "disk", Run: func(ctx context.Context) error {
+		if minFree <= 0 {
+			return nil
+		}
+		usage, err := ReadDiskUsage(dir)
+		if errors.Is(err, ErrDiskUsageUnsupported) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if usage.Free < uint64(minFree) {
+			return fmt.Errorf("%d bytes free, below the minimum of %d", usage.Free, minFree)
+		}
+		return nil
+	}}
+}
+
+// FuncCheck fails with the error message when ok returns false
+// Used for in-process state such as the WebSocket hub running
+func FuncCheck(name, message string, ok func() bool) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error {
+		if !ok() {
+			return errors.New(message)
+		}
+		return nil
+	}}
+}
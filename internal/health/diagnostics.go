@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	ws "fileprintapp/internal/websocket"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// MigrationLevel is the schema version of the database
+// Current and Latest are only set for PostgreSQL, whose schema is migrated;
+// the other backends explain themselves in Note.
+type MigrationLevel struct {
+	Driver   string `json:"driver"`
+	Current  int    `json:"current,omitempty"`  // Highest applied migration
+	Latest   int    `json:"latest,omitempty"`   // Highest migration this build knows
+	Pending  int    `json:"pending"`            // Migrations not applied yet
+	Modified bool   `json:"modified,omitempty"` // An applied migration has since been edited
+	Note     string `json:"note,omitempty"`
+}
+
+// StorageUsage describes the uploads directory and the disk it lives on
+type StorageUsage struct {
+	Path  string     `json:"path"`
+	Files int        `json:"files"`
+	Bytes int64      `json:"bytes"`
+	Disk  *DiskUsage `json:"disk,omitempty"` // Missing where free space can't be read
+}
+
+// Diagnostics is the report served to admins
+type Diagnostics struct {
+	Version       string            `json:"version"`
+	Revision      string            `json:"revision,omitempty"` // VCS commit the binary was built from
+	GoVersion     string            `json:"go_version"`
+	StartedAt     time.Time         `json:"started_at"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	Ready         bool              `json:"ready"`
+	Checks        []CheckResult     `json:"checks"`
+	Config        map[string]string `json:"config"` // Secrets redacted
+	Storage       StorageUsage      `json:"storage"`
+	Migrations    *MigrationLevel   `json:"migrations"`
+	WebSocket     ws.Stats          `json:"websocket"`
+}
+
+// Service answers health probes and builds diagnostics
+type Service struct {
+	version     string
+	startedAt   time.Time
+	checks      []Check
+	config      map[string]string
+	storagePath string
+	migrations  func(ctx context.Context) (*MigrationLevel, error)
+	hub         *ws.Hub
+}
+
+// NewService creates a health service
+// config is the settings summary (secrets already redacted) and migrations
+// reports the schema level of the configured backend
+func NewService(version string, checks []Check, config map[string]string, storagePath string, migrations func(ctx context.Context) (*MigrationLevel, error), hub *ws.Hub) *Service {
+	return &Service{
+		version:     version,
+		startedAt:   time.Now(),
+		checks:      checks,
+		config:      config,
+		storagePath: storagePath,
+		migrations:  migrations,
+		hub:         hub,
+	}
+}
+
+// Ready runs the readiness checks
+func (s *Service) Ready(ctx context.Context) (bool, []CheckResult) {
+	return RunChecks(ctx, s.checks)
+}
+
+// Diagnostics builds the full report
+// Storage usage walks the uploads directory, so it grows with the number of uploads
+func (s *Service) Diagnostics(ctx context.Context) (*Diagnostics, error) {
+	ready, checks := s.Ready(ctx)
+
+	migrations, err := s.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storage, err := s.storageUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Diagnostics{
+		Version:       s.version,
+		Revision:      revision(),
+		GoVersion:     runtime.Version(),
+		StartedAt:     s.startedAt,
+		UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+		Ready:         ready,
+		Checks:        checks,
+		Config:        s.config,
+		Storage:       *storage,
+		Migrations:    migrations,
+		WebSocket:     s.hub.Stats(),
+	}
+	return report, nil
+}
+
+// storageUsage counts the files under the uploads directory and reads its disk's free space
+func (s *Service) storageUsage(ctx context.Context) (*StorageUsage, error) {
+	usage := &StorageUsage{Path: s.storagePath}
+	err := filepath.WalkDir(s.storagePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			usage.Files++
+			usage.Bytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if disk, err := ReadDiskUsage(s.storagePath); err == nil {
+		usage.Disk = disk
+	}
+	return usage, nil
+}
+
+// revision returns the VCS commit recorded in the binary, if any
+func revision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
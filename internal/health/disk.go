@@ -0,0 +1,14 @@
+package health
+
+import "errors"
+
+// ErrDiskUsageUnsupported is returned by ReadDiskUsage on platforms where
+// free space can't be read
+var ErrDiskUsageUnsupported = errors.New("disk usage is not supported on this platform")
+
+// DiskUsage is the size and free space of a filesystem, in bytes
+// Free counts only the space available to unprivileged users
+type DiskUsage struct {
+	Total uint64 `json:"total"`
+	Free  uint64 `json:"free"`
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd)
+
+package health
+
+// ReadDiskUsage always fails with ErrDiskUsageUnsupported on this platform
+func ReadDiskUsage(path string) (*DiskUsage, error) {
+	return nil, ErrDiskUsageUnsupported
+}
@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd
+
+package health
+
+import "syscall"
+
+// ReadDiskUsage reports the size and free space of the filesystem holding path
+func ReadDiskUsage(path string) (*DiskUsage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, err
+	}
+	blockSize := uint64(stat.Bsize)
+	return &DiskUsage{
+		Total: uint64(stat.Blocks) * blockSize,
+		Free:  uint64(stat.Bavail) * blockSize,
+	}, nil
+}
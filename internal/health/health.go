@@ -0,0 +1,67 @@
+// Package health answers liveness and readiness probes and builds the
+// diagnostics report shown to admins
+//
+// Readiness is made of named checks (database, storage, WebSocket hub...)
+// that run concurrently, each with its own timeout.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// checkTimeout bounds each readiness check so a hung database can't hang the probe
+const checkTimeout = 2 * time.Second
+
+// Check results
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+// Check is one readiness check; Run returns nil when the dependency is usable
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// RunChecks runs every check concurrently and reports whether all passed
+// Results are in the order of checks
+func RunChecks(ctx context.Context, checks []Check) (bool, []CheckResult) {
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Run(ctx)
+			results[i] = CheckResult{Name: check.Name, Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				results[i].Status = StatusFailed
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	ready := true
+	for _, result := range results {
+		if result.Status != StatusOK {
+			ready = false
+		}
+	}
+	return ready, results
+}
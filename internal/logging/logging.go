@@ -0,0 +1,84 @@
+// Package logging configures structured logging with log/slog
+//
+// Production logs are JSON, other environments get human-readable text.
+// Records logged with a context carry the request ID of the HTTP request
+// that produced them, plus its trace and span IDs when it is traced, and
+// attributes that look like secrets are redacted.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Redacted replaces the value of every secret attribute
+const Redacted = "[REDACTED]"
+
+// secretKeys are substrings of attribute keys whose values are never logged
+var secretKeys = []string{"password", "secret", "token", "authorization", "cookie", "dsn"}
+
+// Setup makes a logger for the environment the default, for both slog and
+// the standard log package
+func Setup(w io.Writer, environment string, level slog.Level) *slog.Logger {
+	logger := New(w, environment, level)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// New creates a logger writing JSON in production and text otherwise
+func New(w io.Writer, environment string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+
+	var handler slog.Handler
+	if environment == "production" {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog.Level
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(name))
+	return level, err
+}
+
+// redact hides the values of attributes named like secrets
+func redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+	return a
+}
+
+// contextHandler adds the request ID and trace context stored in the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
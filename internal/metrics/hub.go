@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	ws "fileprintapp/internal/websocket"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// hubCollector reports the WebSocket hub's Stats, taken once per scrape
+type hubCollector struct {
+	hub *ws.Hub
+
+	clients         *prometheus.Desc
+	queued          *prometheus.Desc
+	published       *prometheus.Desc
+	droppedMessages *prometheus.Desc
+	droppedClients  *prometheus.Desc
+}
+
+func newHubCollector(hub *ws.Hub) *hubCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "websocket", name), help, nil, nil)
+	}
+	return &hubCollector{
+		hub:             hub,
+		clients:         desc("clients", "Connected WebSocket clients."),
+		queued:          desc("queued_messages", "Events waiting to be sent to clients."),
+		published:       desc("published_messages_total", "Events delivered to the hub."),
+		droppedMessages: desc("dropped_messages_total", "Events dropped because the hub queue was full."),
+		droppedClients:  desc("evicted_clients_total", "Clients disconnected because they could not keep up."),
+	}
+}
+
+func (c *hubCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clients
+	ch <- c.queued
+	ch <- c.published
+	ch <- c.droppedMessages
+	ch <- c.droppedClients
+}
+
+func (c *hubCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.hub.Stats()
+	ch <- prometheus.MustNewConstMetric(c.clients, prometheus.GaugeValue, float64(stats.Clients))
+	ch <- prometheus.MustNewConstMetric(c.queued, prometheus.GaugeValue, float64(stats.Queued))
+	ch <- prometheus.MustNewConstMetric(c.published, prometheus.CounterValue, float64(stats.Published))
+	ch <- prometheus.MustNewConstMetric(c.droppedMessages, prometheus.CounterValue, float64(stats.DroppedMessages))
+	ch <- prometheus.MustNewConstMetric(c.droppedClients, prometheus.CounterValue, float64(stats.DroppedClients))
+}
@@ -0,0 +1,116 @@
+// Package metrics defines the Prometheus metrics served at /metrics
+//
+// Request and upload metrics are package-level and updated by the HTTP
+// layer; WebSocket hub and database pool metrics are read when scraped.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"fileprintapp/internal/domain"
+	ws "fileprintapp/internal/websocket"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "ikonprintzz"
+
+// HTTP request metrics, labelled with the route template (e.g. /api/folders/{id})
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time to serve HTTP requests by route and method. WebSocket connections are not included.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+)
+
+// Upload metrics
+var (
+	UploadBytes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upload_bytes",
+		Help:      "Size of stored uploads.",
+		Buckets:   prometheus.ExponentialBuckets(16<<10, 4, 9), // 16 KiB to 1 GiB
+	})
+
+	UploadDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upload_duration_seconds",
+		Help:      "Time from receiving an upload request to storing the file, including reading the body.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	})
+
+	UploadsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "uploads_rejected_total",
+		Help:      "Uploads that were not stored, by reason.",
+	}, []string{"reason"})
+
+	UploadsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "uploads_in_flight",
+		Help:      "Uploads being received or stored.",
+	})
+)
+
+// Reasons an upload is rejected, used as the reason label of UploadsRejected
+const (
+	RejectBadRequest   = "bad_request"    // Malformed form or missing fields
+	RejectTooLarge     = "too_large"      // Over the maximum file size
+	RejectInvalid      = "invalid"        // File type not allowed or other validation failure
+	RejectNoFolder     = "folder_missing" // Folder doesn't exist
+	RejectShuttingDown = "shutting_down"  // Server is draining for shutdown
+	RejectRateLimited  = "rate_limited"   // Too many uploads to the folder in a short time
+	RejectQuota        = "quota"          // Folder is at its file count or size limit
+	RejectError        = "error"          // Storage or database failure
+)
+
+// RejectReason classifies an error returned while storing an upload
+func RejectReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrTooLarge):
+		return RejectTooLarge
+	case errors.Is(err, domain.ErrValidation):
+		return RejectInvalid
+	case errors.Is(err, domain.ErrNotFound):
+		return RejectNoFolder
+	case errors.Is(err, domain.ErrUnavailable):
+		return RejectShuttingDown
+	case errors.Is(err, domain.ErrLimitExceeded):
+		return RejectQuota
+	default:
+		return RejectError
+	}
+}
+
+// Handler serves every metric in the Prometheus text format: the ones above,
+// the hub's counters, the database connection pool (when db is not nil) and
+// the Go runtime and process
+func Handler(hub *ws.Hub, db *sql.DB) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		UploadBytes,
+		UploadDuration,
+		UploadsRejected,
+		UploadsInFlight,
+		newHubCollector(hub),
+	)
+	if db != nil {
+		registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+	}
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
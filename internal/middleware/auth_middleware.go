@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"fileprintapp/internal/problem"
 	"fileprintapp/internal/usecase"
 	"net/http"
 	"strings"
@@ -28,21 +29,21 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+			problem.Write(w, r, http.StatusUnauthorized, "Authorization header required")
 			return
 		}
 
 		// Extract token from "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid authorization header", http.StatusUnauthorized)
+			problem.Write(w, r, http.StatusUnauthorized, "Invalid authorization header")
 			return
 		}
 
 		token := parts[1]
-		username, err := m.authService.ValidateToken(token)
+		username, err := m.authService.ValidateToken(r.Context(), token)
 		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			problem.Error(w, r, err)
 			return
 		}
 
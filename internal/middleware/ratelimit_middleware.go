@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fileprintapp/internal/problem"
+	"fileprintapp/internal/ratelimit"
+	"log/slog"
+	"net/http"
+	"net/netip"
+)
+
+// RateLimit limits requests per client IP, answering 429 with Retry-After
+// once the client's bucket is empty
+// trustedProxies are the load balancers whose X-Forwarded-For is believed
+// (see ratelimit.ClientIP). A nil limiter lets everything through.
+func RateLimit(limiter *ratelimit.Limiter, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ratelimit.ClientIP(r, trustedProxies)
+			if ok, retryAfter := limiter.Allow(ip); !ok {
+				slog.WarnContext(r.Context(), "rate limited", "client_ip", ip, "path", r.URL.Path)
+				problem.TooManyRequests(w, r, retryAfter, "Too many requests, please retry shortly")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"fileprintapp/internal/middleware"
+	"fileprintapp/internal/problem"
+	"fileprintapp/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+// request sends a request from remote, with an optional X-Forwarded-For,
+// through handler
+func request(handler http.Handler, remote, forwarded string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/api/upload", nil)
+	r.RemoteAddr = remote
+	if forwarded != "" {
+		r.Header.Set("X-Forwarded-For", forwarded)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	t.Run("limited per client", func(t *testing.T) {
+		handler := middleware.RateLimit(ratelimit.New(1), proxies)(ok)
+
+		if rec := request(handler, "203.0.113.7:5000", ""); rec.Code != http.StatusNoContent {
+			t.Fatalf("first request status = %d, want 204", rec.Code)
+		}
+		rec := request(handler, "203.0.113.7:5001", "")
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("second request status = %d, want 429", rec.Code)
+		}
+		// One token a minute, so the wait rounds up to the full minute
+		if got := rec.Header().Get("Retry-After"); got != "60" {
+			t.Errorf("Retry-After = %q, want 60", got)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Errorf("Content-Type = %q, want application/problem+json", ct)
+		}
+		var details problem.Details
+		if err := json.NewDecoder(rec.Body).Decode(&details); err != nil || details.Status != http.StatusTooManyRequests {
+			t.Errorf("problem = %+v, %v, want status 429", details, err)
+		}
+
+		if rec := request(handler, "203.0.113.8:5000", ""); rec.Code != http.StatusNoContent {
+			t.Errorf("another client's status = %d, want 204", rec.Code)
+		}
+	})
+
+	t.Run("spoofed X-Forwarded-For from an untrusted peer", func(t *testing.T) {
+		handler := middleware.RateLimit(ratelimit.New(1), proxies)(ok)
+
+		request(handler, "203.0.113.7:5000", "198.51.100.1")
+		if rec := request(handler, "203.0.113.7:5000", "198.51.100.2"); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("status with a new spoofed address = %d, want 429", rec.Code)
+		}
+	})
+
+	t.Run("clients behind a trusted proxy", func(t *testing.T) {
+		handler := middleware.RateLimit(ratelimit.New(1), proxies)(ok)
+
+		if rec := request(handler, "10.0.0.2:5000", "198.51.100.1, 10.1.2.3"); rec.Code != http.StatusNoContent {
+			t.Fatalf("first client status = %d, want 204", rec.Code)
+		}
+		if rec := request(handler, "10.0.0.2:5000", "198.51.100.2"); rec.Code != http.StatusNoContent {
+			t.Errorf("second client through the same proxy status = %d, want 204", rec.Code)
+		}
+		if rec := request(handler, "10.0.0.3:5000", "198.51.100.1"); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("first client through another proxy status = %d, want 429", rec.Code)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		handler := middleware.RateLimit(nil, nil)(ok)
+		for range 10 {
+			if rec := request(handler, "203.0.113.7:5000", ""); rec.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want 204", rec.Code)
+			}
+		}
+	})
+}
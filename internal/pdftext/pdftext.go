@@ -0,0 +1,206 @@
+// Package pdftext extracts plain text and page counts from PDF files for
+// search indexing and print estimates.
+//
+// Extraction is best effort: it reads the text-showing operators (Tj, TJ, '
+// and ") of uncompressed and Flate-compressed content streams. Text drawn
+// with custom font encodings, embedded in images, or stored in object
+// streams is not recovered.
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MaxText is the most text kept per document
+const MaxText = 64 << 10
+
+// maxStream bounds the size of one decompressed content stream
+const maxStream = 4 << 20
+
+// maxInflated bounds the bytes decompressed from all of a document's
+// streams, so a small upload full of compressed streams can't make the
+// server inflate gigabytes; extraction stops once it is reached
+const maxInflated = 16 << 20
+
+var (
+	streamRe = regexp.MustCompile(`(?s)<<(.*?)>>\s*stream\r?\n`)
+	pageRe   = regexp.MustCompile(`/Type\s*/Page\b`) // Page objects, not /Pages tree nodes
+)
+
+// Info is what pdftext learns about a document
+type Info struct {
+	Text  string // Best-effort plain text, at most MaxText bytes
+	Pages int    // Number of page objects (0 if none could be found)
+}
+
+// ReadFile extracts text and the page count from the PDF at path
+func ReadFile(path string) (*Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Text: Extract(data), Pages: CountPages(data)}, nil
+}
+
+// CountPages counts page objects in a PDF document
+// Pages stored inside compressed object streams are not counted
+func CountPages(data []byte) int {
+	return len(pageRe.FindAllIndex(data, -1))
+}
+
+// Extract returns the text found in a PDF document, truncated to MaxText
+func Extract(data []byte) string {
+	var out strings.Builder
+	budget := int64(maxInflated) // Bytes left to decompress
+
+	for _, loc := range streamRe.FindAllSubmatchIndex(data, -1) {
+		dict := data[loc[2]:loc[3]]
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		stream := data[start : start+end]
+
+		if bytes.Contains(dict, []byte("/FlateDecode")) {
+			inflated, err := inflate(stream, min(maxStream, budget))
+			if err != nil {
+				continue
+			}
+			budget -= int64(len(inflated))
+			stream = inflated
+		} else if bytes.Contains(dict, []byte("/Filter")) {
+			continue // Other filters (images, fonts) carry no text we can read
+		}
+
+		showText(stream, &out)
+		if out.Len() >= MaxText || budget <= 0 {
+			break
+		}
+	}
+
+	text := strings.Join(strings.Fields(out.String()), " ")
+	if len(text) > MaxText {
+		text = strings.ToValidUTF8(text[:MaxText], "")
+	}
+	return text
+}
+
+// inflate decompresses a Flate stream, keeping at most limit bytes
+func inflate(stream []byte, limit int64) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, io.LimitReader(r, limit))
+	if err != nil && buf.Len() == 0 {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// showText appends the literal strings inside BT ... ET text objects
+func showText(stream []byte, out *strings.Builder) {
+	inText := false
+	for i := 0; i < len(stream); i++ {
+		switch c := stream[i]; {
+		case c == '(' && inText:
+			s, n := literal(stream[i:])
+			out.WriteString(s)
+			i += n - 1
+		case c == ']' && inText:
+			out.WriteByte(' ') // End of a TJ array
+		case isOperator(stream, i, "BT"):
+			inText = true
+			i++
+		case isOperator(stream, i, "ET"):
+			inText = false
+			out.WriteByte(' ')
+			i++
+		case isOperator(stream, i, "Td"), isOperator(stream, i, "TD"), isOperator(stream, i, "T*"):
+			out.WriteByte(' ')
+			i++
+		}
+	}
+}
+
+// isOperator reports whether op appears at i as a standalone token
+func isOperator(stream []byte, i int, op string) bool {
+	if !bytes.HasPrefix(stream[i:], []byte(op)) {
+		return false
+	}
+	before := i == 0 || isDelimiter(stream[i-1])
+	after := i+len(op) == len(stream) || isDelimiter(stream[i+len(op)])
+	return before && after
+}
+
+func isDelimiter(c byte) bool {
+	return unicode.IsSpace(rune(c)) || strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// literal decodes a PDF literal string starting at s[0] == '('
+// It returns the decoded text and the number of bytes consumed
+func literal(s []byte) (string, int) {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				b.WriteByte(c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return b.String(), i + 1
+			}
+			b.WriteByte(c)
+		case '\\':
+			if i+1 >= len(s) {
+				return b.String(), len(s)
+			}
+			i++
+			switch e := s[i]; e {
+			case 'n', 'r', 't', 'f', 'b':
+				b.WriteByte(' ')
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v, n := 0, 0
+					for n < 3 && i < len(s) && s[i] >= '0' && s[i] <= '7' {
+						v = v*8 + int(s[i]-'0')
+						i++
+						n++
+					}
+					i--
+					if v >= 0x20 && v < 0x7f {
+						b.WriteByte(byte(v))
+					} else {
+						b.WriteByte(' ')
+					}
+				} else {
+					b.WriteByte(e)
+				}
+			}
+		default:
+			if c >= 0x20 && c < 0x7f {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+	}
+	return b.String(), len(s)
+}
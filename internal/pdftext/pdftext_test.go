@@ -0,0 +1,52 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// flateStream returns a compressed content stream object holding content
+func flateStream(t *testing.T, content string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return "<< /Length " + strconv.Itoa(buf.Len()) + " /Filter /FlateDecode >>\nstream\n" + buf.String() + "\nendstream\n"
+}
+
+func TestExtract(t *testing.T) {
+	doc := "%PDF-1.4\n" +
+		"<< /Length 30 >>\nstream\nBT (Hello) Tj ET\nendstream\n" +
+		flateStream(t, "BT [(com) -20 (pressed)] TJ ET") +
+		"<< /Length 10 /Filter /DCTDecode >>\nstream\nBT (image) Tj ET\nendstream\n"
+
+	if got, want := Extract([]byte(doc)), "Hello compressed"; got != want {
+		t.Errorf("Extract = %q, want %q", got, want)
+	}
+}
+
+// TestExtractInflateLimit checks that extraction stops once maxInflated
+// bytes have been decompressed, however many streams follow
+func TestExtractInflateLimit(t *testing.T) {
+	padding := strings.Repeat(" ", maxStream) // Compresses to a few KiB
+	doc := "%PDF-1.4\n" + flateStream(t, "BT (before) Tj ET")
+	for inflated := 0; inflated < maxInflated; inflated += maxStream {
+		doc += flateStream(t, padding)
+	}
+	doc += flateStream(t, "BT (after) Tj ET")
+
+	if len(doc) > maxInflated/100 {
+		t.Fatalf("test document is %d bytes, meant to be small", len(doc))
+	}
+	if got, want := Extract([]byte(doc)), "before"; got != want {
+		t.Errorf("Extract = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+// Package problem writes RFC 7807 "problem details" error responses
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/logging"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Details is an RFC 7807 problem document
+type Details struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// RequestID identifies the request in the server logs (an RFC 7807 extension member)
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Write sends a problem response with the given status and detail message
+func Write(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Details{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: logging.RequestID(r.Context()),
+	})
+}
+
+// TooManyRequests writes a 429 response telling the client when to retry
+func TooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration, detail string) {
+	seconds := int((retryAfter + time.Second - 1) / time.Second) // Round up so a retry isn't refused again
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	Write(w, r, http.StatusTooManyRequests, detail)
+}
+
+// Error maps a domain error to its status code and writes it
+// Errors of an unknown kind are logged and reported as a generic 500 so
+// internal details (SQL errors, file paths) never reach the client.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	status := StatusCode(err)
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		Write(w, r, status, "An internal error occurred")
+		return
+	}
+	Write(w, r, status, err.Error())
+}
+
+// StatusCode returns the HTTP status code for a domain error
+// A reached quota is a conflict with the folder's state, not a 429: retrying
+// won't help until files are deleted. 429 is left to the rate limiters,
+// which send Retry-After.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domain.ErrLimitExceeded):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,104 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// decode checks that rec holds a problem document with the given status
+// and returns it
+func decode(t *testing.T, rec *httptest.ResponseRecorder, status int) Details {
+	t.Helper()
+	if rec.Code != status {
+		t.Errorf("status = %d, want %d", rec.Code, status)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	var details Details
+	if err := json.NewDecoder(rec.Body).Decode(&details); err != nil {
+		t.Fatalf("invalid problem document: %v", err)
+	}
+	if details.Status != status || details.Title != http.StatusText(status) {
+		t.Errorf("problem = %+v, want status %d", details, status)
+	}
+	return details
+}
+
+// newRequest returns a request to path carrying a request ID
+func newRequest(path string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, path, nil)
+	return r.WithContext(logging.WithRequestID(r.Context(), "req-1"))
+}
+
+func TestTooManyRequests(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		want       string
+	}{
+		{0, "1"},
+		{time.Nanosecond, "1"},
+		{time.Second, "1"},
+		{time.Second + time.Millisecond, "2"},
+		{19999 * time.Millisecond, "20"},
+		{time.Minute, "60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.retryAfter.String(), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			TooManyRequests(rec, newRequest("/api/upload"), tt.retryAfter, "Slow down")
+
+			if got := rec.Header().Get("Retry-After"); got != tt.want {
+				t.Errorf("Retry-After = %q, want %q", got, tt.want)
+			}
+			details := decode(t, rec, http.StatusTooManyRequests)
+			if details.Detail != "Slow down" || details.Instance != "/api/upload" || details.RequestID != "req-1" {
+				t.Errorf("problem = %+v", details)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		detail string
+	}{
+		{"not found", domain.NewError(domain.ErrNotFound, "folder not found"), http.StatusNotFound, "folder not found"},
+		{"validation", domain.NewError(domain.ErrValidation, "name is required"), http.StatusBadRequest, "name is required"},
+		{"conflict", domain.NewError(domain.ErrConflict, "settings changed"), http.StatusConflict, "settings changed"},
+		{"too large", domain.NewError(domain.ErrTooLarge, "file too large"), http.StatusRequestEntityTooLarge, "file too large"},
+		{"unauthorized", domain.NewError(domain.ErrUnauthorized, "invalid token"), http.StatusUnauthorized, "invalid token"},
+		{"unavailable", domain.NewError(domain.ErrUnavailable, "disk full"), http.StatusServiceUnavailable, "disk full"},
+		{"quota", domain.NewError(domain.ErrLimitExceeded, "folder is full"), http.StatusConflict, "folder is full"},
+		{"internal", errors.New("pq: connection refused to 10.0.0.5"), http.StatusInternalServerError, "An internal error occurred"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Error(rec, newRequest("/api/upload"), tt.err)
+
+			details := decode(t, rec, tt.status)
+			if details.RequestID != "req-1" {
+				t.Errorf("request_id = %q, want req-1", details.RequestID)
+			}
+			if details.Detail != tt.detail {
+				t.Errorf("detail = %q, want %q", details.Detail, tt.detail)
+			}
+			// Only the rate limiters say when to retry
+			if got := rec.Header().Get("Retry-After"); got != "" {
+				t.Errorf("Retry-After = %q, want none", got)
+			}
+		})
+	}
+}
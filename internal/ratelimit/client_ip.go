@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP returns the address of the client that sent r
+// X-Forwarded-For is only believed when the connection comes from a trusted
+// proxy: entries are read right to left, skipping trusted proxies, and the
+// first other address is the client. Anyone can send the header, so without
+// trusted proxies the connection's address is used.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	remote := remoteAddr(r)
+	if !remote.IsValid() {
+		return r.RemoteAddr
+	}
+	if !trusted(remote, trustedProxies) {
+		return remote.String()
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break // Garbage from the client; stop at the last address we can vouch for
+		}
+		addr = addr.Unmap()
+		if !trusted(addr, trustedProxies) {
+			return addr.String()
+		}
+		remote = addr
+	}
+	return remote.String()
+}
+
+// remoteAddr parses the connection's address, without the port
+func remoteAddr(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+func trusted(addr netip.Addr, proxies []netip.Prefix) bool {
+	for _, prefix := range proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
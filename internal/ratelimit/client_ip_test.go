@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.1/32"),
+		netip.MustParsePrefix("fd00::/8"),
+	}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string // X-Forwarded-For headers, in order
+		trusted   []netip.Prefix
+		want      string
+	}{
+		{"direct client", "203.0.113.7:51000", nil, proxies, "203.0.113.7"},
+		{"no trusted proxies", "10.0.0.2:51000", []string{"198.51.100.1"}, nil, "10.0.0.2"},
+		{"spoofed header from an untrusted peer", "203.0.113.7:51000", []string{"198.51.100.1"}, proxies, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:51000", []string{"198.51.100.1"}, proxies, "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.2:51000", []string{"198.51.100.1, 192.168.1.1, 10.1.2.3"}, proxies, "198.51.100.1"},
+		{"chain split over headers", "10.0.0.2:51000", []string{"198.51.100.1", "10.1.2.3"}, proxies, "198.51.100.1"},
+		{"spoofed entry before the real client", "10.0.0.2:51000", []string{"1.2.3.4, 198.51.100.1"}, proxies, "198.51.100.1"},
+		{"garbage entry", "10.0.0.2:51000", []string{"not-an-ip, 10.1.2.3"}, proxies, "10.1.2.3"},
+		{"only trusted proxies", "10.0.0.2:51000", []string{"10.1.2.3"}, proxies, "10.1.2.3"},
+		{"trusted proxy without header", "10.0.0.2:51000", nil, proxies, "10.0.0.2"},
+		{"ipv6 through a trusted proxy", "[fd00::1]:51000", []string{"2001:db8::7"}, proxies, "2001:db8::7"},
+		{"ipv4-mapped peer", "[::ffff:10.0.0.2]:51000", []string{"198.51.100.1"}, proxies, "198.51.100.1"},
+		{"ipv4-mapped forwarded entry", "10.0.0.2:51000", []string{"::ffff:198.51.100.1"}, proxies, "198.51.100.1"},
+		{"unparseable remote address", "pipe", []string{"198.51.100.1"}, proxies, "pipe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := ClientIP(r, tt.trusted); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
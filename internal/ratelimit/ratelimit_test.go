@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// rewind moves key's bucket back in time by d, as if d had passed since its
+// last refill
+func rewind(l *Limiter, key string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets[key].last = l.buckets[key].last.Add(-d)
+}
+
+// allowN calls Allow until it refuses, up to max times, and returns how many
+// calls were allowed and the last Retry-After
+func allowN(l *Limiter, key string, max int) (int, time.Duration) {
+	for i := range max {
+		if ok, retryAfter := l.Allow(key); !ok {
+			return i, retryAfter
+		}
+	}
+	return max, 0
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	for _, perMinute := range []int64{0, -1} {
+		l := New(perMinute)
+		if l != nil {
+			t.Fatalf("New(%d) = %v, want nil", perMinute, l)
+		}
+		if allowed, _ := allowN(l, "client", 1000); allowed != 1000 {
+			t.Errorf("New(%d) allowed %d of 1000 calls, want all", perMinute, allowed)
+		}
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	tests := []struct {
+		perMinute int64
+		retry     time.Duration // Time for one token to come back
+	}{
+		{1, time.Minute},
+		{3, 20 * time.Second},
+		{60, time.Second},
+		{120, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.perMinute), func(t *testing.T) {
+			l := New(tt.perMinute)
+			allowed, retryAfter := allowN(l, "client", int(tt.perMinute)+1)
+			if allowed != int(tt.perMinute) {
+				t.Errorf("allowed a burst of %d, want %d", allowed, tt.perMinute)
+			}
+			// Only the time spent in the test has refilled the bucket since
+			if retryAfter > tt.retry || retryAfter < tt.retry-100*time.Millisecond {
+				t.Errorf("Retry-After = %v, want just under %v", retryAfter, tt.retry)
+			}
+
+			// Other keys have buckets of their own
+			if ok, _ := l.Allow("someone-else"); !ok {
+				t.Error("another key was refused")
+			}
+		})
+	}
+}
+
+func TestLimiterRefill(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    int
+	}{
+		{"nothing", 0, 0},
+		{"part of a token", 500 * time.Millisecond, 0},
+		{"one token", time.Second, 1},
+		{"several tokens", 2500 * time.Millisecond, 2},
+		{"capped at the burst", time.Hour, 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(60) // One token a second
+			if allowed, _ := allowN(l, "client", 60); allowed != 60 {
+				t.Fatalf("allowed %d, want the full burst of 60", allowed)
+			}
+
+			rewind(l, "client", tt.elapsed)
+			if allowed, _ := allowN(l, "client", 100); allowed != tt.want {
+				t.Errorf("allowed %d after %v, want %d", allowed, tt.elapsed, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	l := New(60)
+	l.Allow("idle")
+	l.Allow("busy")
+	allowN(l, "busy", 60)
+
+	// A full refill takes a minute; "busy" was emptied a moment ago
+	rewind(l, "idle", time.Minute)
+	rewind(l, "busy", time.Second)
+	l.mu.Lock()
+	l.lastSweep = time.Now().Add(-sweepInterval)
+	l.mu.Unlock()
+
+	l.Allow("trigger")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("full bucket wasn't swept")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("partly refilled bucket was swept")
+	}
+}
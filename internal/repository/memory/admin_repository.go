@@ -1,29 +1,95 @@
 package memory
 
 import (
-	"errors"
+	"context"
 	"fileprintapp/internal/domain"
+	"sort"
+	"sync"
+	"time"
 )
 
 // AdminRepository implements domain.AdminRepository using in-memory storage
 type AdminRepository struct {
-	admin *domain.Admin
+	admins map[string]domain.Admin
+	mu     sync.RWMutex
 }
 
 // NewAdminRepository creates a new admin repository with credentials
 func NewAdminRepository(username, passwordHash string) *AdminRepository {
 	return &AdminRepository{
-		admin: &domain.Admin{
-			Username:     username,
-			PasswordHash: passwordHash,
+		admins: map[string]domain.Admin{
+			username: {Username: username, PasswordHash: passwordHash, CreatedAt: time.Now()},
 		},
 	}
 }
 
 // GetAdminByUsername retrieves admin by username
-func (r *AdminRepository) GetAdminByUsername(username string) (*domain.Admin, error) {
-	if r.admin.Username == username {
-		return r.admin, nil
+func (r *AdminRepository) GetAdminByUsername(ctx context.Context, username string) (*domain.Admin, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	admin, ok := r.admins[username]
+	if !ok {
+		return nil, domain.NewError(domain.ErrNotFound, "admin not found")
+	}
+	return &admin, nil
+}
+
+// CreateAdmin adds an admin
+func (r *AdminRepository) CreateAdmin(ctx context.Context, admin *domain.Admin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.admins[admin.Username]; ok {
+		return domain.NewError(domain.ErrConflict, "admin already exists")
+	}
+	if admin.CreatedAt.IsZero() {
+		admin.CreatedAt = time.Now()
+	}
+	r.admins[admin.Username] = *admin
+	return nil
+}
+
+// UpdatePassword replaces an admin's password hash
+func (r *AdminRepository) UpdatePassword(ctx context.Context, username, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	admin, ok := r.admins[username]
+	if !ok {
+		return domain.NewError(domain.ErrNotFound, "admin not found")
+	}
+	admin.PasswordHash = passwordHash
+	r.admins[username] = admin
+	return nil
+}
+
+// SetDisabled disables or re-enables an admin
+func (r *AdminRepository) SetDisabled(ctx context.Context, username string, disabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	admin, ok := r.admins[username]
+	if !ok {
+		return domain.NewError(domain.ErrNotFound, "admin not found")
+	}
+	admin.Disabled = disabled
+	r.admins[username] = admin
+	return nil
+}
+
+// ListAdmins retrieves every admin, ordered by username
+func (r *AdminRepository) ListAdmins(ctx context.Context) ([]*domain.Admin, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	admins := make([]*domain.Admin, 0, len(r.admins))
+	for _, admin := range r.admins {
+		admin := admin
+		admins = append(admins, &admin)
 	}
-	return nil, errors.New("admin not found")
+	sort.Slice(admins, func(i, j int) bool {
+		return admins[i].Username < admins[j].Username
+	})
+	return admins, nil
 }
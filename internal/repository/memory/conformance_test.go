@@ -0,0 +1,94 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/repository/memory"
+	"fileprintapp/internal/repository/repotest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		files := memory.NewFileRepository()
+		folders := memory.NewFolderRepository(files)
+		return repotest.Repositories{
+			Files:      files,
+			Folders:    folders,
+			Admins:     memory.NewAdminRepository(repotest.AdminUsername, repotest.AdminPasswordHash),
+			UnitOfWork: memory.NewUnitOfWork(files, folders),
+			Search:     memory.NewSearchRepository(files, folders),
+			Settings:   memory.NewSettingsRepository(),
+		}
+	})
+}
+
+// TestUnitOfWorkRollbackKeepsOutsideWrites checks that a rollback reverts
+// only the transaction's own writes
+func TestUnitOfWorkRollbackKeepsOutsideWrites(t *testing.T) {
+	ctx := context.Background()
+	files := memory.NewFileRepository()
+	folders := memory.NewFolderRepository(files)
+	uow := memory.NewUnitOfWork(files, folders)
+
+	for _, id := range []string{"renamed", "deleted"} {
+		if err := folders.CreateFolder(ctx, &domain.Folder{ID: id, Name: id}); err != nil {
+			t.Fatal(err)
+		}
+		if err := files.SaveFile(ctx, &domain.UploadedFile{ID: id + "-file", FolderID: id, FolderName: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errBoom := errors.New("boom")
+	err := uow.Do(ctx, func(tx domain.Repositories) error {
+		if err := tx.Folders.CreateFolder(ctx, &domain.Folder{ID: "created", Name: "created"}); err != nil {
+			return err
+		}
+		if err := tx.Folders.RenameFolder(ctx, "renamed", "Renamed"); err != nil {
+			return err
+		}
+		if err := tx.Files.UpdateFolderName(ctx, "renamed", "Renamed"); err != nil {
+			return err
+		}
+		if err := tx.Folders.DeleteFolder(ctx, "deleted"); err != nil {
+			return err
+		}
+
+		// Writes made outside the transaction while it runs
+		if err := folders.CreateFolder(ctx, &domain.Folder{ID: "outside", Name: "outside"}); err != nil {
+			return err
+		}
+		if err := files.SaveFile(ctx, &domain.UploadedFile{ID: "outside-file", FolderID: "renamed", FolderName: "Renamed"}); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Do returned %v, want %v", err, errBoom)
+	}
+
+	if _, err := folders.GetFolder(ctx, "created"); err == nil {
+		t.Error("folder created in the transaction survived the rollback")
+	}
+	if folder, err := folders.GetFolder(ctx, "renamed"); err != nil || folder.Name != "renamed" {
+		t.Errorf("renamed folder after rollback = %+v, %v, want its old name", folder, err)
+	}
+	if file, err := files.GetFile(ctx, "renamed-file"); err != nil || file.FolderName != "renamed" {
+		t.Errorf("file of the renamed folder after rollback = %+v, %v, want the old folder name", file, err)
+	}
+	if _, err := folders.GetFolder(ctx, "deleted"); err != nil {
+		t.Errorf("deleted folder wasn't restored: %v", err)
+	}
+	if _, err := files.GetFile(ctx, "deleted-file"); err != nil {
+		t.Errorf("file of the deleted folder wasn't restored: %v", err)
+	}
+
+	if _, err := folders.GetFolder(ctx, "outside"); err != nil {
+		t.Errorf("folder created outside the transaction was lost: %v", err)
+	}
+	if _, err := files.GetFile(ctx, "outside-file"); err != nil {
+		t.Errorf("file saved outside the transaction was lost: %v", err)
+	}
+}
@@ -1,15 +1,20 @@
 package memory
 
 import (
-	"errors"
+	"context"
 	"fileprintapp/internal/domain"
+	"sort"
 	"sync"
+	"time"
 )
 
 // FileRepository implements domain.FileRepository using in-memory storage
+// Like the SQL backends it only accepts files whose folder exists, once a
+// FolderRepository has been created over it
 type FileRepository struct {
-	files map[string]*domain.UploadedFile
-	mu    sync.RWMutex
+	files   map[string]*domain.UploadedFile
+	folders *FolderRepository // set by NewFolderRepository
+	mu      sync.RWMutex
 }
 
 // NewFileRepository creates a new in-memory file repository
@@ -19,41 +24,84 @@ func NewFileRepository() *FileRepository {
 	}
 }
 
-// SaveFile saves a file to memory
-func (r *FileRepository) SaveFile(file *domain.UploadedFile) error {
+// SaveFile saves a file to memory, returning domain.ErrNotFound if its folder doesn't exist
+func (r *FileRepository) SaveFile(ctx context.Context, file *domain.UploadedFile) error {
+	return r.saveFile(file, nil)
+}
+
+// saveFile saves a file, recording how to revert the write in log
+func (r *FileRepository) saveFile(file *domain.UploadedFile, log *undoLog) error {
+	// Lock order is folders before files, as in FolderRepository
+	if r.folders != nil {
+		r.folders.mu.RLock()
+		defer r.folders.mu.RUnlock()
+		if _, exists := r.folders.folders[file.FolderID]; !exists {
+			return domain.NewError(domain.ErrNotFound, "folder not found")
+		}
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.files[file.ID] = file
+	if file.UploadedAt.IsZero() {
+		file.UploadedAt = time.Now()
+	}
+	log.add(r.restoreFunc(file.ID, r.files[file.ID]))
+	stored := *file
+	r.files[file.ID] = &stored
 	return nil
 }
 
 // GetFile retrieves a file by ID
-func (r *FileRepository) GetFile(id string) (*domain.UploadedFile, error) {
+func (r *FileRepository) GetFile(ctx context.Context, id string) (*domain.UploadedFile, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	file, exists := r.files[id]
 	if !exists {
-		return nil, errors.New("file not found")
+		return nil, domain.NewError(domain.ErrNotFound, "file not found")
 	}
 	return file, nil
 }
 
-// GetFilesByFolder retrieves all files in a folder
-func (r *FileRepository) GetFilesByFolder(folderID string) ([]*domain.UploadedFile, error) {
+// GetFilesByFolder retrieves all files in a folder, newest first
+func (r *FileRepository) GetFilesByFolder(ctx context.Context, folderID string) ([]*domain.UploadedFile, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	
-	var files []*domain.UploadedFile
+	files := make([]*domain.UploadedFile, 0)
 	for _, file := range r.files {
 		if file.FolderID == folderID {
 			files = append(files, file)
 		}
 	}
+	sortFilesNewestFirst(files)
 	return files, nil
 }
 
-// GetAllFiles retrieves all files
-func (r *FileRepository) GetAllFiles() ([]*domain.UploadedFile, error) {
+// GetFilesByFolders retrieves the files of several folders, keyed by folder ID
+// and newest first
+func (r *FileRepository) GetFilesByFolders(ctx context.Context, folderIDs []string) (map[string][]*domain.UploadedFile, error) {
+	wanted := make(map[string]bool, len(folderIDs))
+	for _, id := range folderIDs {
+		wanted[id] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byFolder := make(map[string][]*domain.UploadedFile)
+	for _, file := range r.files {
+		if wanted[file.FolderID] {
+			byFolder[file.FolderID] = append(byFolder[file.FolderID], file)
+		}
+	}
+	for _, files := range byFolder {
+		sortFilesNewestFirst(files)
+	}
+	return byFolder, nil
+}
+
+// GetAllFiles retrieves all files, newest first
+func (r *FileRepository) GetAllFiles(ctx context.Context) ([]*domain.UploadedFile, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	
@@ -61,17 +109,108 @@ func (r *FileRepository) GetAllFiles() ([]*domain.UploadedFile, error) {
 	for _, file := range r.files {
 		files = append(files, file)
 	}
+	sortFilesNewestFirst(files)
 	return files, nil
 }
 
+// sortFilesNewestFirst orders files like the SQL repositories do
+func sortFilesNewestFirst(files []*domain.UploadedFile) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].UploadedAt.After(files[j].UploadedAt)
+	})
+}
+
 // DeleteFile deletes a file by ID
-func (r *FileRepository) DeleteFile(id string) error {
+func (r *FileRepository) DeleteFile(ctx context.Context, id string) error {
+	return r.deleteFile(id, nil)
+}
+
+// deleteFile deletes a file, recording how to revert the write in log
+func (r *FileRepository) deleteFile(id string, log *undoLog) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
-	if _, exists := r.files[id]; !exists {
-		return errors.New("file not found")
+	file, exists := r.files[id]
+	if !exists {
+		return domain.NewError(domain.ErrNotFound, "file not found")
 	}
+	log.add(r.restoreFunc(id, file))
 	delete(r.files, id)
 	return nil
 }
+
+// UpdateFolderName updates the folder name stored on every file in a folder
+func (r *FileRepository) UpdateFolderName(ctx context.Context, folderID, name string) error {
+	return r.updateFolderName(folderID, name, nil)
+}
+
+// updateFolderName renames a folder's files, recording how to revert the
+// write in log
+func (r *FileRepository) updateFolderName(folderID, name string, log *undoLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, file := range r.files {
+		if file.FolderID == folderID {
+			log.add(r.restoreFunc(id, file))
+			renamed := *file
+			renamed.FolderName = name
+			r.files[id] = &renamed
+		}
+	}
+	return nil
+}
+
+// deleteFolderFiles deletes every file in a folder, recording how to revert
+// the write in log
+func (r *FileRepository) deleteFolderFiles(folderID string, log *undoLog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, file := range r.files {
+		if file.FolderID == folderID {
+			log.add(r.restoreFunc(id, file))
+			delete(r.files, id)
+		}
+	}
+}
+
+// restoreFunc returns a function that puts back file as the stored value for
+// id, or removes id if file is nil
+// The stored value is replaced rather than modified, so file can be the one
+// being overwritten.
+func (r *FileRepository) restoreFunc(id string, file *domain.UploadedFile) func() {
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if file == nil {
+			delete(r.files, id)
+			return
+		}
+		r.files[id] = file
+	}
+}
+
+// folderStats holds the totals of one folder's files
+type folderStats struct {
+	files int
+	bytes int64
+	pages int
+}
+
+// folderStats totals the stored files by folder
+func (r *FileRepository) folderStats() map[string]folderStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := make(map[string]folderStats)
+	for _, file := range r.files {
+		st := stats[file.FolderID]
+		st.files++
+		st.bytes += file.FileSize
+		st.pages += file.PageCount
+		stats[file.FolderID] = st
+	}
+	return stats
+}
+
@@ -1,64 +1,142 @@
 package memory
 
 import (
-	"errors"
+	"context"
 	"fileprintapp/internal/domain"
+	"sort"
 	"sync"
+	"time"
 )
 
 // FolderRepository implements domain.FolderRepository using in-memory storage
+// Folder totals are computed from files on every read
 type FolderRepository struct {
 	folders map[string]*domain.Folder
+	files   *FileRepository
 	mu      sync.RWMutex
 }
 
 // NewFolderRepository creates a new in-memory folder repository
-func NewFolderRepository() *FolderRepository {
-	return &FolderRepository{
+// files is the repository folder totals are derived from; from now on it
+// checks that saved files belong to one of these folders
+func NewFolderRepository(files *FileRepository) *FolderRepository {
+	r := &FolderRepository{
 		folders: make(map[string]*domain.Folder),
+		files:   files,
 	}
+	files.folders = r
+	return r
 }
 
 // CreateFolder creates a new folder
-func (r *FolderRepository) CreateFolder(folder *domain.Folder) error {
+func (r *FolderRepository) CreateFolder(ctx context.Context, folder *domain.Folder) error {
+	return r.createFolder(folder, nil)
+}
+
+// createFolder creates a folder, recording how to revert the write in log
+func (r *FolderRepository) createFolder(folder *domain.Folder, log *undoLog) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.folders[folder.ID] = folder
+	if folder.CreatedAt.IsZero() {
+		folder.CreatedAt = time.Now()
+	}
+	log.add(r.restoreFunc(folder.ID, r.folders[folder.ID]))
+	stored := *folder
+	r.folders[folder.ID] = &stored
 	return nil
 }
 
 // GetFolder retrieves a folder by ID
-func (r *FolderRepository) GetFolder(id string) (*domain.Folder, error) {
+func (r *FolderRepository) GetFolder(ctx context.Context, id string) (*domain.Folder, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	folder, exists := r.folders[id]
 	if !exists {
-		return nil, errors.New("folder not found")
+		return nil, domain.NewError(domain.ErrNotFound, "folder not found")
 	}
-	return folder, nil
+	return withStats(folder, r.files.folderStats()), nil
 }
 
-// GetAllFolders retrieves all folders
-func (r *FolderRepository) GetAllFolders() ([]*domain.Folder, error) {
+// GetAllFolders retrieves all folders, newest first
+func (r *FolderRepository) GetAllFolders(ctx context.Context) ([]*domain.Folder, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	
+	stats := r.files.folderStats()
 	folders := make([]*domain.Folder, 0, len(r.folders))
 	for _, folder := range r.folders {
-		folders = append(folders, folder)
+		folders = append(folders, withStats(folder, stats))
 	}
+	sort.Slice(folders, func(i, j int) bool {
+		return folders[i].CreatedAt.After(folders[j].CreatedAt)
+	})
 	return folders, nil
 }
 
-// UpdateFolderFileCount updates the file count for a folder
-func (r *FolderRepository) UpdateFolderFileCount(folderID string, count int) error {
+// RenameFolder changes a folder's name
+func (r *FolderRepository) RenameFolder(ctx context.Context, id, name string) error {
+	return r.renameFolder(id, name, nil)
+}
+
+// renameFolder renames a folder, recording how to revert the write in log
+func (r *FolderRepository) renameFolder(id, name string, log *undoLog) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	
-	folder, exists := r.folders[folderID]
+
+	folder, exists := r.folders[id]
 	if !exists {
-		return errors.New("folder not found")
+		return domain.NewError(domain.ErrNotFound, "folder not found")
 	}
-	folder.FileCount = count
+	log.add(r.restoreFunc(id, folder))
+	renamed := *folder
+	renamed.Name = name
+	r.folders[id] = &renamed
 	return nil
 }
+
+// DeleteFolder deletes a folder by ID along with its files, like the
+// cascading foreign key of the SQL backends
+func (r *FolderRepository) DeleteFolder(ctx context.Context, id string) error {
+	return r.deleteFolder(id, nil)
+}
+
+// deleteFolder deletes a folder and its files, recording how to revert the
+// write in log
+func (r *FolderRepository) deleteFolder(id string, log *undoLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	folder, exists := r.folders[id]
+	if !exists {
+		return domain.NewError(domain.ErrNotFound, "folder not found")
+	}
+	log.add(r.restoreFunc(id, folder))
+	delete(r.folders, id)
+	r.files.deleteFolderFiles(id, log)
+	return nil
+}
+
+// restoreFunc returns a function that puts back folder as the stored value
+// for id, or removes id if folder is nil
+func (r *FolderRepository) restoreFunc(id string, folder *domain.Folder) func() {
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if folder == nil {
+			delete(r.folders, id)
+			return
+		}
+		r.folders[id] = folder
+	}
+}
+
+// withStats returns a copy of folder with its totals filled in
+func withStats(folder *domain.Folder, stats map[string]folderStats) *domain.Folder {
+	copied := *folder
+	st := stats[folder.ID]
+	copied.FileCount = st.files
+	copied.TotalBytes = st.bytes
+	copied.TotalPages = st.pages
+	return &copied
+}
+
@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ListFiles returns one page of files matching the query
+func (r *FileRepository) ListFiles(ctx context.Context, query domain.FileQuery) (*domain.FilePage, error) {
+	order := query.Sort
+	if order.Field == "" {
+		order = domain.DefaultSort
+	}
+
+	r.mu.RLock()
+	files := make([]*domain.UploadedFile, 0)
+	for _, file := range r.files {
+		if matchesFileQuery(file, query) {
+			files = append(files, file)
+		}
+	}
+	r.mu.RUnlock()
+
+	key := func(f *domain.UploadedFile) interface{} { return fileSortKey(f, order.Field) }
+	page, more, err := paginate(files, order, query.Cursor, query.Limit, key, func(f *domain.UploadedFile) string { return f.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.FilePage{Files: page}
+	if more {
+		last := page[len(page)-1]
+		result.NextCursor = domain.EncodeCursor(order, domain.FileSortValue(last, order.Field), last.ID)
+	}
+	return result, nil
+}
+
+// ListFolders returns one page of folders matching the query
+func (r *FolderRepository) ListFolders(ctx context.Context, query domain.FolderQuery) (*domain.FolderPage, error) {
+	order := query.Sort
+	if order.Field == "" {
+		order = domain.DefaultSort
+	}
+
+	stats := r.files.folderStats()
+	r.mu.RLock()
+	folders := make([]*domain.Folder, 0)
+	for _, folder := range r.folders {
+		if matchesFolderQuery(folder, query) {
+			folders = append(folders, withStats(folder, stats))
+		}
+	}
+	r.mu.RUnlock()
+
+	key := func(f *domain.Folder) interface{} {
+		if order.Field == domain.SortByName {
+			return f.Name
+		}
+		return f.CreatedAt
+	}
+	page, more, err := paginate(folders, order, query.Cursor, query.Limit, key, func(f *domain.Folder) string { return f.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.FolderPage{Folders: page}
+	if more {
+		last := page[len(page)-1]
+		result.NextCursor = domain.EncodeCursor(order, domain.FolderSortValue(last, order.Field), last.ID)
+	}
+	return result, nil
+}
+
+func matchesFileQuery(file *domain.UploadedFile, q domain.FileQuery) bool {
+	if q.FolderID != "" && file.FolderID != q.FolderID {
+		return false
+	}
+	if q.FileType != "" && file.FileType != q.FileType {
+		return false
+	}
+	if !inRange(file.UploadedAt, q.From, q.To) {
+		return false
+	}
+	if q.Search != "" && !containsFold(file.FileName, q.Search) && !containsFold(file.FolderName, q.Search) {
+		return false
+	}
+	return true
+}
+
+func matchesFolderQuery(folder *domain.Folder, q domain.FolderQuery) bool {
+	if !inRange(folder.CreatedAt, q.From, q.To) {
+		return false
+	}
+	if q.Search != "" && !containsFold(folder.Name, q.Search) {
+		return false
+	}
+	return true
+}
+
+// inRange reports whether t is in [from, to), ignoring zero bounds
+func inRange(t, from, to time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && !t.Before(to) {
+		return false
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func fileSortKey(f *domain.UploadedFile, field domain.SortField) interface{} {
+	switch field {
+	case domain.SortByName:
+		return f.FileName
+	case domain.SortBySize:
+		return f.FileSize
+	default:
+		return f.UploadedAt
+	}
+}
+
+// paginate sorts items by (key, id) and returns the page after cursor
+// It reports whether more items follow the page
+func paginate[T any](items []T, order domain.Sort, cursor string, limit int, key func(T) interface{}, id func(T) string) ([]T, bool, error) {
+	dir := -1
+	if order.Ascending {
+		dir = 1
+	}
+
+	compare := func(k1 interface{}, id1 string, k2 interface{}, id2 string) int {
+		if c := compareKeys(k1, k2); c != 0 {
+			return c * dir
+		}
+		return strings.Compare(id1, id2) * dir
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return compare(key(items[i]), id(items[i]), key(items[j]), id(items[j])) < 0
+	})
+
+	if cursor != "" {
+		c, err := domain.DecodeCursor(cursor, order)
+		if err != nil {
+			return nil, false, err
+		}
+		after, err := domain.CursorArg(c, order.Field)
+		if err != nil {
+			return nil, false, err
+		}
+		start := sort.Search(len(items), func(i int) bool {
+			return compare(key(items[i]), id(items[i]), after, c.ID) > 0
+		})
+		items = items[start:]
+	}
+
+	limit = domain.PageSize(limit)
+	if len(items) > limit {
+		return items[:limit], true, nil
+	}
+	return items, false, nil
+}
+
+// compareKeys compares two sort keys of the same type
+func compareKeys(a, b interface{}) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case int64:
+		b := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	default:
+		return strings.Compare(a.(string), b.(string))
+	}
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+)
+
+// SearchRepository implements domain.SearchRepository by scanning the in-memory repositories
+type SearchRepository struct {
+	files   *FileRepository
+	folders *FolderRepository
+}
+
+// NewSearchRepository creates a search repository over in-memory files and folders
+func NewSearchRepository(files *FileRepository, folders *FolderRepository) *SearchRepository {
+	return &SearchRepository{
+		files:   files,
+		folders: folders,
+	}
+}
+
+// Search finds folders and files matching every term, best first
+func (r *SearchRepository) Search(ctx context.Context, terms []string, limit int) ([]*domain.SearchHit, error) {
+	hits := make([]*domain.SearchHit, 0)
+	if len(terms) == 0 {
+		return hits, nil
+	}
+
+	r.folders.mu.RLock()
+	for _, folder := range r.folders.folders {
+		if rank := domain.Rank(terms, domain.RankField{Text: folder.Name, Weight: domain.RankWeightName}); rank > 0 {
+			hits = append(hits, &domain.SearchHit{
+				Kind:       domain.SearchKindFolder,
+				ID:         folder.ID,
+				FolderID:   folder.ID,
+				FolderName: folder.Name,
+				Rank:       rank,
+			})
+		}
+	}
+	r.folders.mu.RUnlock()
+
+	r.files.mu.RLock()
+	for _, file := range r.files.files {
+		rank := domain.Rank(terms,
+			domain.RankField{Text: file.FileName, Weight: domain.RankWeightName},
+			domain.RankField{Text: file.FolderName, Weight: domain.RankWeightFolder},
+			domain.RankField{Text: file.Text, Weight: domain.RankWeightContent},
+		)
+		if rank > 0 {
+			hits = append(hits, &domain.SearchHit{
+				Kind:       domain.SearchKindFile,
+				ID:         file.ID,
+				FolderID:   file.FolderID,
+				FolderName: file.FolderName,
+				FileName:   file.FileName,
+				Rank:       rank,
+			})
+		}
+	}
+	r.files.mu.RUnlock()
+
+	domain.SortSearchHits(hits)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
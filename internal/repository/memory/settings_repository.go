@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+	"sync"
+)
+
+// SettingsRepository implements domain.SettingsRepository using in-memory storage
+type SettingsRepository struct {
+	versions []domain.SettingsVersion // Oldest first
+	mu       sync.RWMutex
+}
+
+// NewSettingsRepository creates a new settings repository with no saved settings
+func NewSettingsRepository() *SettingsRepository {
+	return &SettingsRepository{}
+}
+
+// GetSettings retrieves the newest settings version
+func (r *SettingsRepository) GetSettings(ctx context.Context) (*domain.SettingsVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.versions) == 0 {
+		return nil, domain.NewError(domain.ErrNotFound, "settings not found")
+	}
+	return copySettingsVersion(r.versions[len(r.versions)-1]), nil
+}
+
+// SaveSettings stores a new settings version
+func (r *SettingsRepository) SaveSettings(ctx context.Context, version *domain.SettingsVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.versions {
+		if v.Version == version.Version {
+			return domain.NewError(domain.ErrConflict, "settings version already exists")
+		}
+	}
+	r.versions = append(r.versions, *copySettingsVersion(*version))
+	return nil
+}
+
+// ListSettingsHistory retrieves settings versions, newest first
+func (r *SettingsRepository) ListSettingsHistory(ctx context.Context, limit int) ([]*domain.SettingsVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]*domain.SettingsVersion, 0, limit)
+	for i := len(r.versions) - 1; i >= 0 && len(versions) < limit; i-- {
+		versions = append(versions, copySettingsVersion(r.versions[i]))
+	}
+	return versions, nil
+}
+
+// copySettingsVersion copies a version, including its lists, so callers
+// can't change what is stored
+func copySettingsVersion(v domain.SettingsVersion) *domain.SettingsVersion {
+	v.Settings.AllowedExtensions = append([]string{}, v.Settings.AllowedExtensions...)
+	v.Settings.PriceList = append([]domain.Price{}, v.Settings.PriceList...)
+	v.Settings.OpeningHours = append([]domain.OpeningHours{}, v.Settings.OpeningHours...)
+	return &v
+}
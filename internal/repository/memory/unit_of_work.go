@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+	"sync"
+)
+
+// UnitOfWork implements domain.UnitOfWork for the in-memory repositories
+// Transactions are serialized. Each write made through the transaction's
+// repositories records how to revert itself, and a rollback reverts only
+// those writes, newest first, so writes made outside the transaction in the
+// meantime are kept. Unlike the SQL backends, other readers see a
+// transaction's writes before it commits.
+type UnitOfWork struct {
+	files   *FileRepository
+	folders *FolderRepository
+	mu      sync.Mutex
+}
+
+// NewUnitOfWork creates a transaction runner over the given repositories
+func NewUnitOfWork(files *FileRepository, folders *FolderRepository) *UnitOfWork {
+	return &UnitOfWork{
+		files:   files,
+		folders: folders,
+	}
+}
+
+// Do runs fn atomically, reverting its writes if it fails
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos domain.Repositories) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	log := &undoLog{}
+	err := fn(domain.Repositories{
+		Files:   &txFileRepository{FileRepository: u.files, log: log},
+		Folders: &txFolderRepository{FolderRepository: u.folders, log: log},
+	})
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		log.rollback()
+		return err
+	}
+	return nil
+}
+
+// undoLog records how to revert each write of a transaction, oldest first
+// A nil log records nothing, for writes made outside a transaction.
+type undoLog struct {
+	mu   sync.Mutex
+	undo []func()
+}
+
+// add records a function reverting one write
+func (l *undoLog) add(undo func()) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.undo = append(l.undo, undo)
+}
+
+// rollback reverts every recorded write, newest first
+// Writes record themselves with a repository lock held, so the undo functions,
+// which take those locks, run after mu is released.
+func (l *undoLog) rollback() {
+	l.mu.Lock()
+	undo := l.undo
+	l.undo = nil
+	l.mu.Unlock()
+
+	for i := len(undo) - 1; i >= 0; i-- {
+		undo[i]()
+	}
+}
+
+// txFileRepository is the file repository as seen by a transaction
+type txFileRepository struct {
+	*FileRepository
+	log *undoLog
+}
+
+func (r *txFileRepository) SaveFile(ctx context.Context, file *domain.UploadedFile) error {
+	return r.saveFile(file, r.log)
+}
+
+func (r *txFileRepository) DeleteFile(ctx context.Context, id string) error {
+	return r.deleteFile(id, r.log)
+}
+
+func (r *txFileRepository) UpdateFolderName(ctx context.Context, folderID, name string) error {
+	return r.updateFolderName(folderID, name, r.log)
+}
+
+// txFolderRepository is the folder repository as seen by a transaction
+type txFolderRepository struct {
+	*FolderRepository
+	log *undoLog
+}
+
+func (r *txFolderRepository) CreateFolder(ctx context.Context, folder *domain.Folder) error {
+	return r.createFolder(folder, r.log)
+}
+
+func (r *txFolderRepository) RenameFolder(ctx context.Context, id, name string) error {
+	return r.renameFolder(id, name, r.log)
+}
+
+func (r *txFolderRepository) DeleteFolder(ctx context.Context, id string) error {
+	return r.deleteFolder(id, r.log)
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fileprintapp/internal/domain"
 )
@@ -8,7 +9,27 @@ import (
 // AdminRepository implements domain.AdminRepository using PostgreSQL (Neon)
 // Manages admin user authentication data in persistent storage
 type AdminRepository struct {
-	db *sql.DB // PostgreSQL database connection
+	db querier // PostgreSQL connection pool or transaction
+}
+
+// adminSelect selects admins; columns match scanAdmin
+const adminSelect = `
+		SELECT username, password_hash, created_at, disabled
+		FROM admins`
+
+// scanAdmin reads one row produced by adminSelect
+func scanAdmin(row scanner) (*domain.Admin, error) {
+	admin := &domain.Admin{}
+	err := row.Scan(
+		&admin.Username,
+		&admin.PasswordHash,
+		&admin.CreatedAt,
+		&admin.Disabled,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return admin, nil
 }
 
 // NewAdminRepository creates a new PostgreSQL-backed admin repository
@@ -25,45 +46,103 @@ func NewAdminRepository(db *sql.DB) *AdminRepository {
 // GetAdminByUsername retrieves admin credentials by username
 // Used during login to verify credentials
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - username: Admin username to look up
 // Returns:
 //   - *domain.Admin: Admin entity with hashed password
-//   - error: sql.ErrNoRows if not found, other errors on query failure
-func (r *AdminRepository) GetAdminByUsername(username string) (*domain.Admin, error) {
-	query := `
-		SELECT username, password_hash
-		FROM admins
+//   - error: domain.ErrNotFound if not found, other errors on query failure
+func (r *AdminRepository) GetAdminByUsername(ctx context.Context, username string) (*domain.Admin, error) {
+	query := adminSelect + `
 		WHERE username = $1
 	`
 
-	admin := &domain.Admin{}
-	
 	// Scan database row into admin struct
-	err := r.db.QueryRow(query, username).Scan(
-		&admin.Username,
-		&admin.PasswordHash,
-	)
+	admin, err := scanAdmin(r.db.QueryRowContext(ctx, query, username))
+	if err != nil {
+		return nil, translateError(err, "admin")
+	}
+
+	return admin, nil
+}
+
+// ListAdmins retrieves every admin account, ordered by username
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+// Returns:
+//   - []*domain.Admin: All admins, including disabled ones
+//   - error: nil on success, error on query failure
+func (r *AdminRepository) ListAdmins(ctx context.Context) ([]*domain.Admin, error) {
+	query := adminSelect + `
+		ORDER BY username
+	`
 
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return admin, nil
+	admins := make([]*domain.Admin, 0)
+	for rows.Next() {
+		admin, err := scanAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, admin)
+	}
+
+	return admins, rows.Err()
 }
 
 // CreateAdmin creates a new admin user in database
-// Used during initial setup or when adding new admins
+// Used by the create-admin command and to seed the configured admin
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - admin: Admin entity with username and hashed password
 // Returns:
-//   - error: nil on success, error on duplicate username or query failure
-func (r *AdminRepository) CreateAdmin(admin *domain.Admin) error {
+//   - error: nil on success, domain.ErrConflict if the username is taken, other errors on query failure
+func (r *AdminRepository) CreateAdmin(ctx context.Context, admin *domain.Admin) error {
 	query := `
 		INSERT INTO admins (username, password_hash)
 		VALUES ($1, $2)
-		ON CONFLICT (username) DO NOTHING
 	`
 
-	_, err := r.db.Exec(query, admin.Username, admin.PasswordHash)
-	return err
+	_, err := r.db.ExecContext(ctx, query, admin.Username, admin.PasswordHash)
+	return translateError(err, "admin")
+}
+
+// UpdatePassword replaces an admin's password hash
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - username: Admin to update
+//   - passwordHash: New bcrypt-hashed password
+// Returns:
+//   - error: nil on success, domain.ErrNotFound if admin not found, other errors on query failure
+func (r *AdminRepository) UpdatePassword(ctx context.Context, username, passwordHash string) error {
+	query := `UPDATE admins SET password_hash = $1 WHERE username = $2`
+
+	result, err := r.db.ExecContext(ctx, query, passwordHash, username)
+	if err != nil {
+		return err
+	}
+
+	return expectRows(result, "admin")
+}
+
+// SetDisabled disables or re-enables an admin account
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - username: Admin to update
+//   - disabled: true to block logins and reject the admin's tokens
+// Returns:
+//   - error: nil on success, domain.ErrNotFound if admin not found, other errors on query failure
+func (r *AdminRepository) SetDisabled(ctx context.Context, username string, disabled bool) error {
+	query := `UPDATE admins SET disabled = $1 WHERE username = $2`
+
+	result, err := r.db.ExecContext(ctx, query, disabled, username)
+	if err != nil {
+		return err
+	}
+
+	return expectRows(result, "admin")
 }
@@ -0,0 +1,46 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/database"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/repository/postgres"
+	"fileprintapp/internal/repository/repotest"
+	"testing"
+)
+
+// TestConformance runs the suite against the database in TEST_POSTGRES_DSN
+// Every subtest empties the schema's tables first, so don't point it at a
+// database whose data you want to keep
+func TestConformance(t *testing.T) {
+	db, err := sql.Open("postgres", repotest.PostgresDSN(t))
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.RunMigrations(db); err != nil {
+		t.Fatalf("running migrations: %v", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		ctx := context.Background()
+		if _, err := db.ExecContext(ctx, `TRUNCATE uploaded_files, folders, admins, settings`); err != nil {
+			t.Fatalf("emptying tables: %v", err)
+		}
+		admins := postgres.NewAdminRepository(db)
+		admin := &domain.Admin{Username: repotest.AdminUsername, PasswordHash: repotest.AdminPasswordHash}
+		if err := admins.CreateAdmin(ctx, admin); err != nil {
+			t.Fatalf("seeding admin: %v", err)
+		}
+
+		return repotest.Repositories{
+			Files:      postgres.NewFileRepository(db),
+			Folders:    postgres.NewFolderRepository(db),
+			Admins:     admins,
+			UnitOfWork: postgres.NewUnitOfWork(db),
+			Search:     postgres.NewSearchRepository(db),
+			Settings:   postgres.NewSettingsRepository(db),
+		}
+	})
+}
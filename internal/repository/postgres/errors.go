@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fileprintapp/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes translated into domain errors
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// translateError converts driver errors into domain errors
+// Parameters:
+//   - err: Error returned by a query (may be nil)
+//   - what: Name of the entity involved, used in client-facing messages
+// Returns:
+//   - error: domain.ErrNotFound / domain.ErrConflict kinds where recognized,
+//     otherwise the original error
+func translateError(err error, what string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewError(domain.ErrNotFound, what+" not found")
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return domain.NewError(domain.ErrConflict, what+" already exists")
+		case foreignKeyViolation:
+			// The only foreign key is uploaded_files.folder_id
+			return domain.NewError(domain.ErrNotFound, "folder not found")
+		}
+	}
+	return err
+}
+
+// expectRows returns a not-found error if a statement affected nothing
+func expectRows(result sql.Result, what string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return translateError(sql.ErrNoRows, what)
+	}
+	return nil
+}
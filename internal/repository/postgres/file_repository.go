@@ -1,15 +1,18 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fileprintapp/internal/domain"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // FileRepository implements domain.FileRepository using PostgreSQL (Neon)
 // This provides persistent storage for uploaded files metadata
 type FileRepository struct {
-	db *sql.DB // PostgreSQL database connection
+	db querier // PostgreSQL connection pool or transaction
 }
 
 // NewFileRepository creates a new PostgreSQL-backed file repository
@@ -26,17 +29,18 @@ func NewFileRepository(db *sql.DB) *FileRepository {
 // SaveFile persists file metadata to PostgreSQL database
 // This stores information about uploaded files for admin viewing
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - file: File entity containing all upload information
 // Returns:
 //   - error: nil on success, error if database operation fails
-func (r *FileRepository) SaveFile(file *domain.UploadedFile) error {
+func (r *FileRepository) SaveFile(ctx context.Context, file *domain.UploadedFile) error {
 	// SQL query to insert file record into database
 	// Uses COALESCE to handle NULL values safely
 	query := `
 		INSERT INTO uploaded_files (
 			id, folder_id, folder_name, file_name, 
-			file_size, file_type, file_path, uploaded_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			file_size, file_type, file_path, uploaded_at, page_count, content_text
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	// Set upload timestamp to current time if not already set
@@ -45,7 +49,8 @@ func (r *FileRepository) SaveFile(file *domain.UploadedFile) error {
 	}
 
 	// Execute INSERT query with file data
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(
+		ctx,
 		query,
 		file.ID,
 		file.FolderID,
@@ -55,21 +60,24 @@ func (r *FileRepository) SaveFile(file *domain.UploadedFile) error {
 		file.FileType,
 		file.FilePath,
 		file.UploadedAt,
+		file.PageCount,
+		file.Text,
 	)
 
-	return err
+	return translateError(err, "file")
 }
 
 // GetFile retrieves a single file by its unique ID
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - id: Unique identifier of the file to retrieve
 // Returns:
 //   - *domain.UploadedFile: File entity if found
-//   - error: sql.ErrNoRows if not found, other errors on query failure
-func (r *FileRepository) GetFile(id string) (*domain.UploadedFile, error) {
+//   - error: domain.ErrNotFound if not found, other errors on query failure
+func (r *FileRepository) GetFile(ctx context.Context, id string) (*domain.UploadedFile, error) {
 	query := `
 		SELECT id, folder_id, folder_name, file_name, 
-		       file_size, file_type, file_path, uploaded_at
+		       file_size, file_type, file_path, uploaded_at, page_count
 		FROM uploaded_files
 		WHERE id = $1
 	`
@@ -77,7 +85,7 @@ func (r *FileRepository) GetFile(id string) (*domain.UploadedFile, error) {
 	file := &domain.UploadedFile{}
 	
 	// Scan database row into file struct
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&file.ID,
 		&file.FolderID,
 		&file.FolderName,
@@ -86,10 +94,11 @@ func (r *FileRepository) GetFile(id string) (*domain.UploadedFile, error) {
 		&file.FileType,
 		&file.FilePath,
 		&file.UploadedAt,
+		&file.PageCount,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "file")
 	}
 
 	return file, nil
@@ -98,21 +107,22 @@ func (r *FileRepository) GetFile(id string) (*domain.UploadedFile, error) {
 // GetFilesByFolder retrieves all files belonging to a specific folder
 // Useful for displaying files grouped by user-created folders
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - folderID: Unique identifier of the folder
 // Returns:
 //   - []*domain.UploadedFile: Slice of files in the folder (empty if none)
 //   - error: nil on success, error on query failure
-func (r *FileRepository) GetFilesByFolder(folderID string) ([]*domain.UploadedFile, error) {
+func (r *FileRepository) GetFilesByFolder(ctx context.Context, folderID string) ([]*domain.UploadedFile, error) {
 	query := `
 		SELECT id, folder_id, folder_name, file_name, 
-		       file_size, file_type, file_path, uploaded_at
+		       file_size, file_type, file_path, uploaded_at, page_count
 		FROM uploaded_files
 		WHERE folder_id = $1
 		ORDER BY uploaded_at DESC
 	`
 
 	// Execute query to get all matching rows
-	rows, err := r.db.Query(query, folderID)
+	rows, err := r.db.QueryContext(ctx, query, folderID)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +141,7 @@ func (r *FileRepository) GetFilesByFolder(folderID string) ([]*domain.UploadedFi
 			&file.FileType,
 			&file.FilePath,
 			&file.UploadedAt,
+			&file.PageCount,
 		)
 		if err != nil {
 			return nil, err
@@ -141,20 +152,73 @@ func (r *FileRepository) GetFilesByFolder(folderID string) ([]*domain.UploadedFi
 	return files, rows.Err()
 }
 
+// GetFilesByFolders retrieves the files of several folders in one query
+// Used to list a page of folders with their files
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - folderIDs: Unique identifiers of the folders
+// Returns:
+//   - map[string][]*domain.UploadedFile: Files keyed by folder ID, newest first
+//     (folders without files are left out)
+//   - error: nil on success, error on query failure
+func (r *FileRepository) GetFilesByFolders(ctx context.Context, folderIDs []string) (map[string][]*domain.UploadedFile, error) {
+	byFolder := make(map[string][]*domain.UploadedFile)
+	if len(folderIDs) == 0 {
+		return byFolder, nil
+	}
+
+	query := `
+		SELECT id, folder_id, folder_name, file_name,
+		       file_size, file_type, file_path, uploaded_at, page_count
+		FROM uploaded_files
+		WHERE folder_id = ANY($1)
+		ORDER BY uploaded_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(folderIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		file := &domain.UploadedFile{}
+		err := rows.Scan(
+			&file.ID,
+			&file.FolderID,
+			&file.FolderName,
+			&file.FileName,
+			&file.FileSize,
+			&file.FileType,
+			&file.FilePath,
+			&file.UploadedAt,
+			&file.PageCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		byFolder[file.FolderID] = append(byFolder[file.FolderID], file)
+	}
+
+	return byFolder, rows.Err()
+}
+
 // GetAllFiles retrieves all uploaded files from database
 // Ordered by upload time (newest first) for admin dashboard
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 // Returns:
 //   - []*domain.UploadedFile: All files in system
 //   - error: nil on success, error on query failure
-func (r *FileRepository) GetAllFiles() ([]*domain.UploadedFile, error) {
+func (r *FileRepository) GetAllFiles(ctx context.Context) ([]*domain.UploadedFile, error) {
 	query := `
 		SELECT id, folder_id, folder_name, file_name, 
-		       file_size, file_type, file_path, uploaded_at
+		       file_size, file_type, file_path, uploaded_at, page_count
 		FROM uploaded_files
 		ORDER BY uploaded_at DESC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -172,6 +236,7 @@ func (r *FileRepository) GetAllFiles() ([]*domain.UploadedFile, error) {
 			&file.FileType,
 			&file.FilePath,
 			&file.UploadedAt,
+			&file.PageCount,
 		)
 		if err != nil {
 			return nil, err
@@ -186,27 +251,33 @@ func (r *FileRepository) GetAllFiles() ([]*domain.UploadedFile, error) {
 // Note: This only deletes database record, not the physical file
 // Physical file deletion is handled by the use case layer
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - id: Unique identifier of file to delete
 // Returns:
-//   - error: nil on success, error if file not found or query fails
-func (r *FileRepository) DeleteFile(id string) error {
+//   - error: nil on success, domain.ErrNotFound if file not found, other errors on query failure
+func (r *FileRepository) DeleteFile(ctx context.Context, id string) error {
 	query := `DELETE FROM uploaded_files WHERE id = $1`
 	
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
 
-	// Check if any row was actually deleted
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+	// Return a not-found error if no row was actually deleted
+	return expectRows(result, "file")
+}
 
-	// Return error if file didn't exist
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
-	}
+// UpdateFolderName updates the denormalized folder name on every file in a folder
+// Called when a folder is renamed so file listings show the new name
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - folderID: Unique identifier of the folder
+//   - name: New folder name
+// Returns:
+//   - error: nil on success (including when the folder has no files), error on query failure
+func (r *FileRepository) UpdateFolderName(ctx context.Context, folderID, name string) error {
+	query := `UPDATE uploaded_files SET folder_name = $1 WHERE folder_id = $2`
 
-	return nil
+	_, err := r.db.ExecContext(ctx, query, name, folderID)
+	return err
 }
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fileprintapp/internal/domain"
 	"time"
@@ -9,7 +10,43 @@ import (
 // FolderRepository implements domain.FolderRepository using PostgreSQL (Neon)
 // Manages folder metadata in persistent storage
 type FolderRepository struct {
-	db *sql.DB // PostgreSQL database connection
+	db querier // PostgreSQL connection pool or transaction
+}
+
+// folderSelect selects folders with totals derived from their files
+// Counts are always computed, so they can't drift under concurrent uploads
+// Columns match scanFolder
+const folderSelect = `
+		SELECT id, name, created_at, s.file_count, s.total_bytes, s.total_pages
+		FROM folders
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS file_count,
+			       COALESCE(SUM(u.file_size), 0) AS total_bytes,
+			       COALESCE(SUM(u.page_count), 0) AS total_pages
+			FROM uploaded_files u
+			WHERE u.folder_id = folders.id
+		) s`
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanFolder reads one row produced by folderSelect
+func scanFolder(row scanner) (*domain.Folder, error) {
+	folder := &domain.Folder{}
+	err := row.Scan(
+		&folder.ID,
+		&folder.Name,
+		&folder.CreatedAt,
+		&folder.FileCount,
+		&folder.TotalBytes,
+		&folder.TotalPages,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return folder, nil
 }
 
 // NewFolderRepository creates a new PostgreSQL-backed folder repository
@@ -26,14 +63,15 @@ func NewFolderRepository(db *sql.DB) *FolderRepository {
 // CreateFolder persists a new folder to the database
 // Folders are used to organize uploaded files by user-defined names
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - folder: Folder entity with ID, name, and metadata
 // Returns:
 //   - error: nil on success, error on duplicate ID or query failure
-func (r *FolderRepository) CreateFolder(folder *domain.Folder) error {
+func (r *FolderRepository) CreateFolder(ctx context.Context, folder *domain.Folder) error {
 	// SQL query to insert folder record
 	query := `
-		INSERT INTO folders (id, name, created_at, file_count)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO folders (id, name, created_at)
+		VALUES ($1, $2, $3)
 		ON CONFLICT (id) DO NOTHING
 	`
 
@@ -43,42 +81,33 @@ func (r *FolderRepository) CreateFolder(folder *domain.Folder) error {
 	}
 
 	// Execute INSERT query
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(
+		ctx,
 		query,
 		folder.ID,
 		folder.Name,
 		folder.CreatedAt,
-		folder.FileCount,
 	)
 
-	return err
+	return translateError(err, "folder")
 }
 
 // GetFolder retrieves a single folder by its unique ID
 // Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 //   - id: Unique identifier of the folder
 // Returns:
 //   - *domain.Folder: Folder entity if found
-//   - error: sql.ErrNoRows if not found, other errors on query failure
-func (r *FolderRepository) GetFolder(id string) (*domain.Folder, error) {
-	query := `
-		SELECT id, name, created_at, file_count
-		FROM folders
+//   - error: domain.ErrNotFound if not found, other errors on query failure
+func (r *FolderRepository) GetFolder(ctx context.Context, id string) (*domain.Folder, error) {
+	query := folderSelect + `
 		WHERE id = $1
 	`
 
-	folder := &domain.Folder{}
-	
 	// Scan database row into folder struct
-	err := r.db.QueryRow(query, id).Scan(
-		&folder.ID,
-		&folder.Name,
-		&folder.CreatedAt,
-		&folder.FileCount,
-	)
-
+	folder, err := scanFolder(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "folder")
 	}
 
 	return folder, nil
@@ -86,17 +115,17 @@ func (r *FolderRepository) GetFolder(id string) (*domain.Folder, error) {
 
 // GetAllFolders retrieves all folders from database
 // Ordered by creation time (newest first)
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
 // Returns:
 //   - []*domain.Folder: All folders in system
 //   - error: nil on success, error on query failure
-func (r *FolderRepository) GetAllFolders() ([]*domain.Folder, error) {
-	query := `
-		SELECT id, name, created_at, file_count
-		FROM folders
+func (r *FolderRepository) GetAllFolders(ctx context.Context) ([]*domain.Folder, error) {
+	query := folderSelect + `
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -105,13 +134,7 @@ func (r *FolderRepository) GetAllFolders() ([]*domain.Folder, error) {
 	// Collect all folders into a slice
 	folders := make([]*domain.Folder, 0)
 	for rows.Next() {
-		folder := &domain.Folder{}
-		err := rows.Scan(
-			&folder.ID,
-			&folder.Name,
-			&folder.CreatedAt,
-			&folder.FileCount,
-		)
+		folder, err := scanFolder(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -121,34 +144,39 @@ func (r *FolderRepository) GetAllFolders() ([]*domain.Folder, error) {
 	return folders, rows.Err()
 }
 
-// UpdateFolderFileCount updates the number of files in a folder
-// This is called when files are added or removed
+// RenameFolder changes a folder's name
+// The denormalized folder_name on its files is updated by FileRepository.UpdateFolderName
 // Parameters:
-//   - folderID: Unique identifier of the folder
-//   - count: New file count (should be >= 0)
+//   - ctx: Request context; cancelling it aborts the query
+//   - id: Unique identifier of the folder
+//   - name: New folder name
 // Returns:
-//   - error: nil on success, error if folder not found or query fails
-func (r *FolderRepository) UpdateFolderFileCount(folderID string, count int) error {
-	query := `
-		UPDATE folders 
-		SET file_count = $1
-		WHERE id = $2
-	`
+//   - error: nil on success, domain.ErrNotFound if folder not found, other errors on query failure
+func (r *FolderRepository) RenameFolder(ctx context.Context, id, name string) error {
+	query := `UPDATE folders SET name = $1 WHERE id = $2`
 
-	result, err := r.db.Exec(query, count, folderID)
+	result, err := r.db.ExecContext(ctx, query, name, id)
 	if err != nil {
 		return err
 	}
 
-	// Check if folder exists
-	rowsAffected, err := result.RowsAffected()
+	return expectRows(result, "folder")
+}
+
+// DeleteFolder removes a folder from the database
+// File rows are removed by ON DELETE CASCADE; physical files are handled by the use case layer
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - id: Unique identifier of the folder to delete
+// Returns:
+//   - error: nil on success, domain.ErrNotFound if folder not found, other errors on query failure
+func (r *FolderRepository) DeleteFolder(ctx context.Context, id string) error {
+	query := `DELETE FROM folders WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
-	}
-
-	return nil
+	return expectRows(result, "folder")
 }
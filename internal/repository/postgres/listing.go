@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fileSortColumns maps sort fields to uploaded_files columns
+var fileSortColumns = map[domain.SortField]string{
+	domain.SortByDate: "uploaded_at",
+	domain.SortByName: "file_name",
+	domain.SortBySize: "file_size",
+}
+
+// folderSortColumns maps sort fields to folders columns
+var folderSortColumns = map[domain.SortField]string{
+	domain.SortByDate: "created_at",
+	domain.SortByName: "name",
+}
+
+// listQuery accumulates WHERE conditions and positional arguments
+type listQuery struct {
+	where []string
+	args  []interface{}
+}
+
+// arg appends a value and returns its placeholder
+func (q *listQuery) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// between restricts column to [from, to), ignoring zero bounds
+func (q *listQuery) between(column string, from, to time.Time) {
+	if !from.IsZero() {
+		q.where = append(q.where, column+" >= "+q.arg(from))
+	}
+	if !to.IsZero() {
+		q.where = append(q.where, column+" < "+q.arg(to))
+	}
+}
+
+// after applies keyset pagination: rows strictly after the cursor in sort order
+func (q *listQuery) after(column, cursor string, order domain.Sort) error {
+	if cursor == "" {
+		return nil
+	}
+	c, err := domain.DecodeCursor(cursor, order)
+	if err != nil {
+		return err
+	}
+	value, err := domain.CursorArg(c, order.Field)
+	if err != nil {
+		return err
+	}
+
+	op := "<"
+	if order.Ascending {
+		op = ">"
+	}
+	q.where = append(q.where, fmt.Sprintf("(%s, id) %s (%s, %s)", column, op, q.arg(value), q.arg(c.ID)))
+	return nil
+}
+
+// build renders the final statement, fetching one extra row to detect a next page
+func (q *listQuery) build(selectFrom, column string, order domain.Sort, limit int) string {
+	dir := "DESC"
+	if order.Ascending {
+		dir = "ASC"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(selectFrom)
+	if len(q.where) > 0 {
+		sb.WriteString(" WHERE " + strings.Join(q.where, " AND "))
+	}
+	fmt.Fprintf(&sb, " ORDER BY %s %s, id %s LIMIT %s", column, dir, dir, q.arg(limit+1))
+	return sb.String()
+}
+
+// likePattern escapes LIKE wildcards in a search term
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s) + "%"
+}
+
+// ListFiles retrieves one page of files matching the query
+// Uses keyset pagination on (sort column, id) so pages stay stable under inserts
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - query: Filters, sort order, cursor and page size
+//
+// Returns:
+//   - *domain.FilePage: Matching files and the cursor of the next page
+//   - error: domain.ErrValidation for a bad cursor, other errors on query failure
+func (r *FileRepository) ListFiles(ctx context.Context, query domain.FileQuery) (*domain.FilePage, error) {
+	order := query.Sort
+	if order.Field == "" {
+		order = domain.DefaultSort
+	}
+	column := fileSortColumns[order.Field]
+	limit := domain.PageSize(query.Limit)
+
+	q := &listQuery{}
+	if query.FolderID != "" {
+		q.where = append(q.where, "folder_id = "+q.arg(query.FolderID))
+	}
+	if query.FileType != "" {
+		q.where = append(q.where, "file_type = "+q.arg(query.FileType))
+	}
+	q.between("uploaded_at", query.From, query.To)
+	if query.Search != "" {
+		p := q.arg(likePattern(query.Search))
+		q.where = append(q.where, fmt.Sprintf("(file_name ILIKE %s OR folder_name ILIKE %s)", p, p))
+	}
+	if err := q.after(column, query.Cursor, order); err != nil {
+		return nil, err
+	}
+
+	stmt := q.build(`
+		SELECT id, folder_id, folder_name, file_name,
+		       file_size, file_type, file_path, uploaded_at, page_count
+		FROM uploaded_files`, column, order, limit)
+
+	rows, err := r.db.QueryContext(ctx, stmt, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make([]*domain.UploadedFile, 0)
+	for rows.Next() {
+		file := &domain.UploadedFile{}
+		err := rows.Scan(
+			&file.ID,
+			&file.FolderID,
+			&file.FolderName,
+			&file.FileName,
+			&file.FileSize,
+			&file.FileType,
+			&file.FilePath,
+			&file.UploadedAt,
+			&file.PageCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &domain.FilePage{Files: files}
+	if len(files) > limit {
+		page.Files = files[:limit]
+		last := page.Files[limit-1]
+		page.NextCursor = domain.EncodeCursor(order, domain.FileSortValue(last, order.Field), last.ID)
+	}
+	return page, nil
+}
+
+// ListFolders retrieves one page of folders matching the query
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - query: Filters, sort order, cursor and page size
+//
+// Returns:
+//   - *domain.FolderPage: Matching folders and the cursor of the next page
+//   - error: domain.ErrValidation for a bad cursor, other errors on query failure
+func (r *FolderRepository) ListFolders(ctx context.Context, query domain.FolderQuery) (*domain.FolderPage, error) {
+	order := query.Sort
+	if order.Field == "" {
+		order = domain.DefaultSort
+	}
+	column := folderSortColumns[order.Field]
+	limit := domain.PageSize(query.Limit)
+
+	q := &listQuery{}
+	q.between("created_at", query.From, query.To)
+	if query.Search != "" {
+		q.where = append(q.where, "name ILIKE "+q.arg(likePattern(query.Search)))
+	}
+	if err := q.after(column, query.Cursor, order); err != nil {
+		return nil, err
+	}
+
+	stmt := q.build(folderSelect, column, order, limit)
+
+	rows, err := r.db.QueryContext(ctx, stmt, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := make([]*domain.Folder, 0)
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &domain.FolderPage{Folders: folders}
+	if len(folders) > limit {
+		page.Folders = folders[:limit]
+		last := page.Folders[limit-1]
+		page.NextCursor = domain.EncodeCursor(order, domain.FolderSortValue(last, order.Field), last.ID)
+	}
+	return page, nil
+}
@@ -0,0 +1,15 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// querier is the subset of *sql.DB and *sql.Tx used by the repositories
+// It lets the same repository code run either directly against the
+// connection pool or inside a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/domain"
+	"strings"
+)
+
+// SearchRepository implements domain.SearchRepository using PostgreSQL full-text search
+// Backed by the tsvector columns and GIN indexes from migration 003_search
+type SearchRepository struct {
+	db querier // PostgreSQL connection pool or transaction
+}
+
+// NewSearchRepository creates a new PostgreSQL-backed search repository
+// Parameters:
+//   - db: Active database connection to Neon PostgreSQL
+// Returns:
+//   - Configured SearchRepository ready for use
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{
+		db: db,
+	}
+}
+
+// Search finds folders and files matching every term, best first
+// Each term matches as a word prefix so results update as the user types
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - terms: Lowercase words from domain.SearchTerms (letters and digits only)
+//   - limit: Maximum number of hits
+// Returns:
+//   - []*domain.SearchHit: Ranked hits (empty if nothing matches)
+//   - error: nil on success, error on query failure
+func (r *SearchRepository) Search(ctx context.Context, terms []string, limit int) ([]*domain.SearchHit, error) {
+	hits := make([]*domain.SearchHit, 0)
+	if len(terms) == 0 {
+		return hits, nil
+	}
+
+	// "john cv" becomes "john:* & cv:*"; terms are alphanumeric so need no quoting
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+
+	query := `
+		WITH q AS (SELECT to_tsquery('simple', $1) AS query)
+		SELECT 'folder', id, id, name, '', ts_rank(search_vector, q.query) AS rank
+		FROM folders, q
+		WHERE search_vector @@ q.query
+		UNION ALL
+		SELECT 'file', id, folder_id, folder_name, file_name, ts_rank(search_vector, q.query) AS rank
+		FROM uploaded_files, q
+		WHERE search_vector @@ q.query
+		ORDER BY rank DESC, 4, 5
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, strings.Join(prefixes, " & "), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		hit := &domain.SearchHit{}
+		err := rows.Scan(
+			&hit.Kind,
+			&hit.ID,
+			&hit.FolderID,
+			&hit.FolderName,
+			&hit.FileName,
+			&hit.Rank,
+		)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
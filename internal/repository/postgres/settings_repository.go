@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fileprintapp/internal/domain"
+)
+
+// SettingsRepository implements domain.SettingsRepository using PostgreSQL (Neon)
+// Each saved version is one row; the settings themselves are stored as JSONB
+type SettingsRepository struct {
+	db querier // PostgreSQL connection pool or transaction
+}
+
+// settingsSelect selects settings versions; columns match scanSettingsVersion
+const settingsSelect = `
+		SELECT version, settings, changed_by, changed_at
+		FROM settings`
+
+// scanSettingsVersion reads one row produced by settingsSelect
+func scanSettingsVersion(row scanner) (*domain.SettingsVersion, error) {
+	version := &domain.SettingsVersion{}
+	var settings []byte
+	err := row.Scan(
+		&version.Version,
+		&settings,
+		&version.ChangedBy,
+		&version.ChangedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(settings, &version.Settings); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// NewSettingsRepository creates a new PostgreSQL-backed settings repository
+// Parameters:
+//   - db: Active database connection to Neon PostgreSQL
+// Returns:
+//   - Configured SettingsRepository ready for use
+func NewSettingsRepository(db *sql.DB) *SettingsRepository {
+	return &SettingsRepository{
+		db: db,
+	}
+}
+
+// GetSettings retrieves the newest settings version
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+// Returns:
+//   - *domain.SettingsVersion: The settings in effect
+//   - error: domain.ErrNotFound if settings were never saved, other errors on query failure
+func (r *SettingsRepository) GetSettings(ctx context.Context) (*domain.SettingsVersion, error) {
+	query := settingsSelect + `
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	version, err := scanSettingsVersion(r.db.QueryRowContext(ctx, query))
+	if err != nil {
+		return nil, translateError(err, "settings")
+	}
+
+	return version, nil
+}
+
+// SaveSettings stores a new settings version
+// The version number is the primary key, so two admins saving on top of the
+// same version can't both succeed
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - version: Settings version to insert
+// Returns:
+//   - error: nil on success, domain.ErrConflict if the version number is taken, other errors on query failure
+func (r *SettingsRepository) SaveSettings(ctx context.Context, version *domain.SettingsVersion) error {
+	settings, err := json.Marshal(version.Settings)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO settings (version, settings, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err = r.db.ExecContext(ctx, query, version.Version, settings, version.ChangedBy, version.ChangedAt)
+	return translateError(err, "settings version")
+}
+
+// ListSettingsHistory retrieves settings versions, newest first
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the query
+//   - limit: Maximum number of versions to return
+// Returns:
+//   - []*domain.SettingsVersion: Saved versions, newest first
+//   - error: nil on success, error on query failure
+func (r *SettingsRepository) ListSettingsHistory(ctx context.Context, limit int) ([]*domain.SettingsVersion, error) {
+	query := settingsSelect + `
+		ORDER BY version DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]*domain.SettingsVersion, 0)
+	for rows.Next() {
+		version, err := scanSettingsVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/domain"
+	"fmt"
+)
+
+// UnitOfWork implements domain.UnitOfWork with PostgreSQL transactions
+type UnitOfWork struct {
+	db *sql.DB // PostgreSQL database connection
+}
+
+// NewUnitOfWork creates a transaction runner for the given database
+// Parameters:
+//   - db: Active database connection to Neon PostgreSQL
+// Returns:
+//   - Configured UnitOfWork ready for use
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+// Do runs fn inside a single database transaction
+// The transaction is committed when fn returns nil and rolled back otherwise
+// (including when ctx is cancelled)
+// Parameters:
+//   - ctx: Request context; cancelling it aborts the transaction
+//   - fn: Work to perform with transaction-bound repositories
+// Returns:
+//   - error: fn's error, or an error from begin/commit
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos domain.Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	repos := domain.Repositories{
+		Files:   &FileRepository{db: tx},
+		Folders: &FolderRepository{db: tx},
+	}
+
+	if err := fn(repos); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
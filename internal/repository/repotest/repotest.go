@@ -0,0 +1,900 @@
+// Package repotest is a conformance suite for domain repository implementations.
+//
+// Every backend (memory, sqlite, postgres) must behave the same way, so each
+// one runs this suite from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) repotest.Repositories {
+//			files := memory.NewFileRepository()
+//			folders := memory.NewFolderRepository(files)
+//			return repotest.Repositories{
+//				Files:      files,
+//				Folders:    folders,
+//				Admins:     memory.NewAdminRepository(repotest.AdminUsername, repotest.AdminPasswordHash),
+//				UnitOfWork: memory.NewUnitOfWork(files, folders),
+//				Search:     memory.NewSearchRepository(files, folders),
+//				Settings:   memory.NewSettingsRepository(),
+//			}
+//		})
+//	}
+//
+// Database-backed suites should call PostgresDSN to skip when no test
+// database is configured. Several cases write concurrently, so run the suite
+// with -race.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The admin account every Factory must seed
+const (
+	AdminUsername     = "conformance-admin"
+	AdminPasswordHash = "conformance-hash"
+)
+
+// Repositories is one fresh, empty set of repositories under test
+type Repositories struct {
+	Files      domain.FileRepository
+	Folders    domain.FolderRepository
+	Admins     domain.AdminRepository
+	UnitOfWork domain.UnitOfWork
+	Search     domain.SearchRepository
+	Settings   domain.SettingsRepository
+}
+
+// Factory returns empty repositories (apart from the seeded admin) for one subtest
+type Factory func(t *testing.T) Repositories
+
+// PostgresDSN returns the test database DSN from TEST_POSTGRES_DSN, skipping
+// the test when it isn't set
+func PostgresDSN(t *testing.T) string {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN not set")
+	}
+	return dsn
+}
+
+// Run executes the whole conformance suite
+func Run(t *testing.T, newRepos Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repos Repositories)
+	}{
+		{"FileNotFound", testFileNotFound},
+		{"FolderNotFound", testFolderNotFound},
+		{"SaveAndGetFile", testSaveAndGetFile},
+		{"SaveFileMissingFolder", testSaveFileMissingFolder},
+		{"FilesNewestFirst", testFilesNewestFirst},
+		{"FilesByFolder", testFilesByFolder},
+		{"DeleteFile", testDeleteFile},
+		{"FilesByFolders", testFilesByFolders},
+		{"FoldersNewestFirst", testFoldersNewestFirst},
+		{"FolderTotals", testFolderTotals},
+		{"FolderTotalsConcurrent", testFolderTotalsConcurrent},
+		{"RenameFolder", testRenameFolder},
+		{"DeleteFolder", testDeleteFolder},
+		{"ListFilesPages", testListFilesPages},
+		{"ListFilesFilters", testListFilesFilters},
+		{"ListFolders", testListFolders},
+		{"ConcurrentWrites", testConcurrentWrites},
+		{"UnitOfWorkCommit", testUnitOfWorkCommit},
+		{"UnitOfWorkRollback", testUnitOfWorkRollback},
+		{"Search", testSearch},
+		{"AdminLookup", testAdminLookup},
+		{"AdminWrites", testAdminWrites},
+		{"Settings", testSettings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepos(t))
+		})
+	}
+}
+
+func testFileNotFound(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	if _, err := repos.Files.GetFile(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetFile(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repos.Files.DeleteFile(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteFile(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func testFolderNotFound(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	if _, err := repos.Folders.GetFolder(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetFolder(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repos.Folders.RenameFolder(ctx, "missing", "name"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("RenameFolder(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repos.Folders.DeleteFolder(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteFolder(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func testSaveAndGetFile(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+
+	file := &domain.UploadedFile{
+		ID:         "file-1",
+		FolderID:   folder.ID,
+		FolderName: folder.Name,
+		FileName:   "cv.pdf",
+		FileSize:   1234,
+		FileType:   "pdf",
+		FilePath:   "/uploads/folder-1/file-1.pdf",
+	}
+	if err := repos.Files.SaveFile(ctx, file); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+
+	got, err := repos.Files.GetFile(ctx, "file-1")
+	if err != nil {
+		t.Fatalf("GetFile: %v", err)
+	}
+	if got.FolderID != file.FolderID || got.FolderName != file.FolderName || got.FileName != file.FileName ||
+		got.FileSize != file.FileSize || got.FileType != file.FileType || got.FilePath != file.FilePath {
+		t.Errorf("GetFile = %+v, want %+v", got, file)
+	}
+	if got.UploadedAt.IsZero() {
+		t.Error("SaveFile did not set UploadedAt")
+	}
+}
+
+func testSaveFileMissingFolder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	orphan := newFile("file-1", &domain.Folder{ID: "missing", Name: "Missing"}, time.Now())
+	if err := repos.Files.SaveFile(ctx, orphan); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SaveFile(missing folder) = %v, want ErrNotFound", err)
+	}
+	if _, err := repos.Files.GetFile(ctx, orphan.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetFile after rejected SaveFile = %v, want ErrNotFound", err)
+	}
+}
+
+func testFilesNewestFirst(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+
+	base := time.Now().Add(-time.Hour)
+	mustSaveFile(t, repos, "old", folder, base)
+	mustSaveFile(t, repos, "newest", folder, base.Add(2*time.Minute))
+	mustSaveFile(t, repos, "middle", folder, base.Add(time.Minute))
+
+	files, err := repos.Files.GetAllFiles(ctx)
+	if err != nil {
+		t.Fatalf("GetAllFiles: %v", err)
+	}
+	assertFileIDs(t, "GetAllFiles", files, "newest", "middle", "old")
+
+	files, err = repos.Files.GetFilesByFolder(ctx, folder.ID)
+	if err != nil {
+		t.Fatalf("GetFilesByFolder: %v", err)
+	}
+	assertFileIDs(t, "GetFilesByFolder", files, "newest", "middle", "old")
+}
+
+func testFilesByFolder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	a := mustCreateFolder(t, repos, "folder-a", time.Now())
+	b := mustCreateFolder(t, repos, "folder-b", time.Now())
+	empty := mustCreateFolder(t, repos, "folder-empty", time.Now())
+
+	mustSaveFile(t, repos, "a-1", a, time.Now())
+	mustSaveFile(t, repos, "b-1", b, time.Now())
+
+	files, err := repos.Files.GetFilesByFolder(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetFilesByFolder: %v", err)
+	}
+	assertFileIDs(t, "GetFilesByFolder(a)", files, "a-1")
+
+	files, err = repos.Files.GetFilesByFolder(ctx, empty.ID)
+	if err != nil {
+		t.Fatalf("GetFilesByFolder(empty): %v", err)
+	}
+	if files == nil || len(files) != 0 {
+		t.Errorf("GetFilesByFolder(empty) = %#v, want empty non-nil slice", files)
+	}
+
+	all, err := repos.Files.GetAllFiles(ctx)
+	if err != nil {
+		t.Fatalf("GetAllFiles: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("GetAllFiles returned %d files, want 2", len(all))
+	}
+}
+
+func testFilesByFolders(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	a := mustCreateFolder(t, repos, "folder-a", time.Now())
+	b := mustCreateFolder(t, repos, "folder-b", time.Now())
+	other := mustCreateFolder(t, repos, "folder-other", time.Now())
+	empty := mustCreateFolder(t, repos, "folder-empty", time.Now())
+
+	base := time.Now().Add(-time.Hour)
+	mustSaveFile(t, repos, "a-old", a, base)
+	mustSaveFile(t, repos, "a-new", a, base.Add(time.Minute))
+	mustSaveFile(t, repos, "b-1", b, base)
+	mustSaveFile(t, repos, "other-1", other, base)
+
+	byFolder, err := repos.Files.GetFilesByFolders(ctx, []string{a.ID, b.ID, empty.ID, "missing"})
+	if err != nil {
+		t.Fatalf("GetFilesByFolders: %v", err)
+	}
+	if len(byFolder) != 2 {
+		t.Errorf("GetFilesByFolders returned %d folders, want only the 2 with files", len(byFolder))
+	}
+	assertFileIDs(t, "GetFilesByFolders[a]", byFolder[a.ID], "a-new", "a-old")
+	assertFileIDs(t, "GetFilesByFolders[b]", byFolder[b.ID], "b-1")
+
+	byFolder, err = repos.Files.GetFilesByFolders(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetFilesByFolders(nil): %v", err)
+	}
+	if len(byFolder) != 0 {
+		t.Errorf("GetFilesByFolders(nil) returned %d folders, want none", len(byFolder))
+	}
+}
+
+func testDeleteFile(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+	mustSaveFile(t, repos, "keep", folder, time.Now())
+	mustSaveFile(t, repos, "remove", folder, time.Now())
+
+	if err := repos.Files.DeleteFile(ctx, "remove"); err != nil {
+		t.Fatalf("DeleteFile: %v", err)
+	}
+	if _, err := repos.Files.GetFile(ctx, "remove"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetFile after delete = %v, want ErrNotFound", err)
+	}
+	if err := repos.Files.DeleteFile(ctx, "remove"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("second DeleteFile = %v, want ErrNotFound", err)
+	}
+
+	files, err := repos.Files.GetFilesByFolder(ctx, folder.ID)
+	if err != nil {
+		t.Fatalf("GetFilesByFolder: %v", err)
+	}
+	assertFileIDs(t, "GetFilesByFolder", files, "keep")
+}
+
+func testFoldersNewestFirst(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	mustCreateFolder(t, repos, "old", base)
+	mustCreateFolder(t, repos, "newest", base.Add(2*time.Minute))
+	mustCreateFolder(t, repos, "middle", base.Add(time.Minute))
+
+	folders, err := repos.Folders.GetAllFolders(ctx)
+	if err != nil {
+		t.Fatalf("GetAllFolders: %v", err)
+	}
+
+	var ids []string
+	for _, folder := range folders {
+		ids = append(ids, folder.ID)
+	}
+	if fmt.Sprint(ids) != fmt.Sprint([]string{"newest", "middle", "old"}) {
+		t.Errorf("GetAllFolders order = %v, want [newest middle old]", ids)
+	}
+}
+
+func testFolderTotals(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+	mustCreateFolder(t, repos, "empty", time.Now().Add(-time.Minute))
+
+	for i, pages := range []int{1, 3, 5} {
+		file := newFile(fmt.Sprintf("file-%d", i), folder, time.Now())
+		file.FileSize = int64(100 * (i + 1))
+		file.PageCount = pages
+		if err := repos.Files.SaveFile(ctx, file); err != nil {
+			t.Fatalf("SaveFile: %v", err)
+		}
+	}
+	assertTotals(t, repos, folder.ID, 3, 600, 9)
+	assertTotals(t, repos, "empty", 0, 0, 0)
+
+	if err := repos.Files.DeleteFile(ctx, "file-1"); err != nil {
+		t.Fatalf("DeleteFile: %v", err)
+	}
+	assertTotals(t, repos, folder.ID, 2, 400, 6)
+
+	// Every way of reading a folder reports the same totals
+	all, err := repos.Folders.GetAllFolders(ctx)
+	if err != nil {
+		t.Fatalf("GetAllFolders: %v", err)
+	}
+	page, err := repos.Folders.ListFolders(ctx, domain.FolderQuery{})
+	if err != nil {
+		t.Fatalf("ListFolders: %v", err)
+	}
+	for _, list := range [][]*domain.Folder{all, page.Folders} {
+		if len(list) != 2 || list[0].ID != folder.ID {
+			t.Fatalf("folder list = %d folders, want folder-1 first", len(list))
+		}
+		if got := list[0]; got.FileCount != 2 || got.TotalBytes != 400 || got.TotalPages != 6 {
+			t.Errorf("listed totals = %d files, %d bytes, %d pages, want 2, 400, 6",
+				got.FileCount, got.TotalBytes, got.TotalPages)
+		}
+	}
+}
+
+// testFolderTotalsConcurrent uploads and deletes in parallel; cached counters
+// written after each change lose updates here, derived totals can't
+func testFolderTotalsConcurrent(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+
+	const (
+		existing = 10
+		uploads  = 20
+	)
+	for i := 0; i < existing; i++ {
+		mustSaveFile(t, repos, fmt.Sprintf("old-%d", i), folder, time.Now())
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, existing+uploads)
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repos.UnitOfWork.Do(ctx, func(tx domain.Repositories) error {
+				return tx.Files.SaveFile(ctx, newFile(fmt.Sprintf("new-%d", i), folder, time.Now()))
+			})
+		}(i)
+	}
+	for i := 0; i < existing; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repos.UnitOfWork.Do(ctx, func(tx domain.Repositories) error {
+				return tx.Files.DeleteFile(ctx, fmt.Sprintf("old-%d", i))
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent write: %v", err)
+		}
+	}
+	assertTotals(t, repos, folder.ID, uploads, 100*uploads, 0)
+}
+
+func testRenameFolder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	a := mustCreateFolder(t, repos, "folder-a", time.Now())
+	b := mustCreateFolder(t, repos, "folder-b", time.Now())
+	mustSaveFile(t, repos, "in-a", a, time.Now())
+	mustSaveFile(t, repos, "in-b", b, time.Now())
+
+	if err := repos.Folders.RenameFolder(ctx, a.ID, "Renamed"); err != nil {
+		t.Fatalf("RenameFolder: %v", err)
+	}
+	if err := repos.Files.UpdateFolderName(ctx, a.ID, "Renamed"); err != nil {
+		t.Fatalf("UpdateFolderName: %v", err)
+	}
+
+	folder, err := repos.Folders.GetFolder(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetFolder: %v", err)
+	}
+	if folder.Name != "Renamed" {
+		t.Errorf("folder name = %q, want Renamed", folder.Name)
+	}
+
+	for id, want := range map[string]string{"in-a": "Renamed", "in-b": b.Name} {
+		file, err := repos.Files.GetFile(ctx, id)
+		if err != nil {
+			t.Fatalf("GetFile(%s): %v", id, err)
+		}
+		if file.FolderName != want {
+			t.Errorf("GetFile(%s).FolderName = %q, want %q", id, file.FolderName, want)
+		}
+	}
+}
+
+func testDeleteFolder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	keep := mustCreateFolder(t, repos, "keep", time.Now())
+	drop := mustCreateFolder(t, repos, "drop", time.Now())
+	mustSaveFile(t, repos, "kept", keep, time.Now())
+	mustSaveFile(t, repos, "dropped", drop, time.Now())
+
+	if err := repos.Folders.DeleteFolder(ctx, drop.ID); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+	if _, err := repos.Folders.GetFolder(ctx, drop.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetFolder after DeleteFolder = %v, want ErrNotFound", err)
+	}
+	if _, err := repos.Folders.GetFolder(ctx, keep.ID); err != nil {
+		t.Errorf("GetFolder(keep): %v", err)
+	}
+
+	// The folder's files go with it
+	files, err := repos.Files.GetFilesByFolder(ctx, drop.ID)
+	if err != nil {
+		t.Fatalf("GetFilesByFolder: %v", err)
+	}
+	assertFileIDs(t, "GetFilesByFolder after DeleteFolder", files)
+	if _, err := repos.Files.GetFile(ctx, "dropped"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetFile(dropped) = %v, want ErrNotFound", err)
+	}
+	assertTotals(t, repos, keep.ID, 1, 100, 0)
+}
+
+func testListFilesPages(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+
+	// Two files share a timestamp so the ID tie-breaker is exercised
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	mustSaveFile(t, repos, "a", folder, base)
+	mustSaveFile(t, repos, "b", folder, base.Add(time.Minute))
+	mustSaveFile(t, repos, "c", folder, base.Add(time.Minute))
+	mustSaveFile(t, repos, "d", folder, base.Add(2*time.Minute))
+	mustSaveFile(t, repos, "e", folder, base.Add(3*time.Minute))
+
+	for _, tt := range []struct {
+		sort domain.Sort
+		want []string
+	}{
+		{domain.DefaultSort, []string{"e", "d", "c", "b", "a"}},
+		{domain.Sort{Field: domain.SortByDate, Ascending: true}, []string{"a", "b", "c", "d", "e"}},
+		{domain.Sort{Field: domain.SortByName, Ascending: true}, []string{"a", "b", "c", "d", "e"}},
+	} {
+		var got []*domain.UploadedFile
+		query := domain.FileQuery{Sort: tt.sort, Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages > len(tt.want) {
+				t.Fatalf("ListFiles(%s) did not terminate", tt.sort)
+			}
+			page, err := repos.Files.ListFiles(ctx, query)
+			if err != nil {
+				t.Fatalf("ListFiles(%s): %v", tt.sort, err)
+			}
+			got = append(got, page.Files...)
+			if page.NextCursor == "" {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		assertFileIDs(t, "ListFiles("+tt.sort.String()+")", got, tt.want...)
+	}
+
+	other := domain.Sort{Field: domain.SortByName}
+	first, err := repos.Files.ListFiles(ctx, domain.FileQuery{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListFiles: %v", err)
+	}
+	_, err = repos.Files.ListFiles(ctx, domain.FileQuery{Sort: other, Cursor: first.NextCursor})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("ListFiles with a cursor from another sort = %v, want ErrValidation", err)
+	}
+}
+
+func testListFilesFilters(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	a := mustCreateFolder(t, repos, "folder-a", time.Now())
+	b := mustCreateFolder(t, repos, "folder-b", time.Now())
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	mustSaveFile(t, repos, "a-old", a, base)
+	mustSaveFile(t, repos, "a-new", a, base.Add(time.Minute))
+	image := newFile("b-photo", b, base.Add(2*time.Minute))
+	image.FileName, image.FileType = "Holiday_Photo.JPG", "jpg"
+	if err := repos.Files.SaveFile(ctx, image); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		query domain.FileQuery
+		want  []string
+	}{
+		{"folder", domain.FileQuery{FolderID: a.ID}, []string{"a-new", "a-old"}},
+		{"type", domain.FileQuery{FileType: "jpg"}, []string{"b-photo"}},
+		{"from", domain.FileQuery{From: base.Add(time.Minute)}, []string{"b-photo", "a-new"}},
+		{"to", domain.FileQuery{To: base.Add(time.Minute)}, []string{"a-old"}},
+		{"search", domain.FileQuery{Search: "photo"}, []string{"b-photo"}},
+		{"search folder name", domain.FileQuery{Search: "FOLDER-A"}, []string{"a-new", "a-old"}},
+		{"search wildcard", domain.FileQuery{Search: "_photo"}, []string{"b-photo"}},
+		{"search literal", domain.FileQuery{Search: "%"}, []string{}},
+	} {
+		page, err := repos.Files.ListFiles(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("ListFiles(%s): %v", tt.name, err)
+		}
+		assertFileIDs(t, "ListFiles("+tt.name+")", page.Files, tt.want...)
+	}
+}
+
+func testListFolders(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	mustCreateFolder(t, repos, "old", base)
+	mustCreateFolder(t, repos, "middle", base.Add(time.Minute))
+	mustCreateFolder(t, repos, "newest", base.Add(2*time.Minute))
+
+	page, err := repos.Folders.ListFolders(ctx, domain.FolderQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListFolders: %v", err)
+	}
+	if len(page.Folders) != 2 || page.Folders[0].ID != "newest" || page.NextCursor == "" {
+		t.Fatalf("ListFolders first page = %d folders, cursor %q", len(page.Folders), page.NextCursor)
+	}
+	page, err = repos.Folders.ListFolders(ctx, domain.FolderQuery{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("ListFolders: %v", err)
+	}
+	if len(page.Folders) != 1 || page.Folders[0].ID != "old" || page.NextCursor != "" {
+		t.Errorf("ListFolders last page = %d folders, cursor %q", len(page.Folders), page.NextCursor)
+	}
+
+	page, err = repos.Folders.ListFolders(ctx, domain.FolderQuery{Search: "midd"})
+	if err != nil {
+		t.Fatalf("ListFolders: %v", err)
+	}
+	if len(page.Folders) != 1 || page.Folders[0].ID != "middle" {
+		t.Errorf("ListFolders(search) returned %d folders", len(page.Folders))
+	}
+}
+
+func testConcurrentWrites(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repos.Files.SaveFile(ctx, &domain.UploadedFile{
+				ID:         fmt.Sprintf("file-%d", i),
+				FolderID:   folder.ID,
+				FolderName: folder.Name,
+				FileName:   fmt.Sprintf("file-%d.pdf", i),
+				FileType:   "pdf",
+				FilePath:   fmt.Sprintf("/uploads/file-%d.pdf", i),
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent SaveFile: %v", err)
+		}
+	}
+
+	files, err := repos.Files.GetFilesByFolder(ctx, folder.ID)
+	if err != nil {
+		t.Fatalf("GetFilesByFolder: %v", err)
+	}
+	if len(files) != writers {
+		t.Errorf("got %d files after concurrent writes, want %d", len(files), writers)
+	}
+}
+
+func testUnitOfWorkCommit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+
+	err := repos.UnitOfWork.Do(ctx, func(tx domain.Repositories) error {
+		if err := tx.Files.SaveFile(ctx, newFile("file-1", folder, time.Now())); err != nil {
+			return err
+		}
+		return tx.Folders.RenameFolder(ctx, folder.ID, "Renamed")
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	if _, err := repos.Files.GetFile(ctx, "file-1"); err != nil {
+		t.Errorf("GetFile after commit: %v", err)
+	}
+	got, err := repos.Folders.GetFolder(ctx, folder.ID)
+	if err != nil {
+		t.Fatalf("GetFolder: %v", err)
+	}
+	if got.Name != "Renamed" || got.FileCount != 1 {
+		t.Errorf("folder after commit = %q with %d files, want Renamed with 1", got.Name, got.FileCount)
+	}
+}
+
+func testUnitOfWorkRollback(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	folder := mustCreateFolder(t, repos, "folder-1", time.Now())
+	errBoom := errors.New("boom")
+
+	err := repos.UnitOfWork.Do(ctx, func(tx domain.Repositories) error {
+		if err := tx.Files.SaveFile(ctx, newFile("file-1", folder, time.Now())); err != nil {
+			return err
+		}
+		if err := tx.Folders.RenameFolder(ctx, folder.ID, "Renamed"); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Do returned %v, want %v", err, errBoom)
+	}
+
+	if _, err := repos.Files.GetFile(ctx, "file-1"); err == nil {
+		t.Error("file saved in rolled back transaction is still visible")
+	}
+	got, err := repos.Folders.GetFolder(ctx, folder.ID)
+	if err != nil {
+		t.Fatalf("GetFolder: %v", err)
+	}
+	if got.Name != folder.Name || got.FileCount != 0 {
+		t.Errorf("folder after rollback = %q with %d files, want %q with 0", got.Name, got.FileCount, folder.Name)
+	}
+}
+
+func testSearch(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	john := mustCreateFolder(t, repos, "john", time.Now())
+	other := mustCreateFolder(t, repos, "other", time.Now())
+	if err := repos.Folders.RenameFolder(ctx, john.ID, "John Smith"); err != nil {
+		t.Fatalf("RenameFolder: %v", err)
+	}
+	john.Name = "John Smith"
+
+	cv := newFile("cv", john, time.Now())
+	cv.FileName = "Curriculum_Vitae.pdf"
+	letter := newFile("letter", other, time.Now())
+	letter.FileName = "cover-letter.pdf"
+	letter.Text = "Dear hiring manager, please find attached the CV of John Smith"
+	for _, file := range []*domain.UploadedFile{cv, letter} {
+		if err := repos.Files.SaveFile(ctx, file); err != nil {
+			t.Fatalf("SaveFile(%s): %v", file.ID, err)
+		}
+	}
+
+	search := func(q string) []*domain.SearchHit {
+		t.Helper()
+		hits, err := repos.Search.Search(ctx, domain.SearchTerms(q), 10)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", q, err)
+		}
+		return hits
+	}
+
+	// The folder itself outranks its file, which outranks a mention in PDF text
+	hits := search("john")
+	if len(hits) != 3 {
+		t.Fatalf("Search(john) returned %d hits, want 3", len(hits))
+	}
+	if hits[0].Kind != domain.SearchKindFolder || hits[0].ID != john.ID {
+		t.Errorf("Search(john)[0] = %s %s, want folder %s", hits[0].Kind, hits[0].ID, john.ID)
+	}
+	if hits[1].ID != "cv" || hits[2].ID != "letter" {
+		t.Errorf("Search(john) files = [%s %s], want [cv letter]", hits[1].ID, hits[2].ID)
+	}
+
+	// Prefixes match as the user types; every term must match
+	if hits := search("curric"); len(hits) != 1 || hits[0].ID != "cv" {
+		t.Errorf("Search(curric) = %d hits, want cv", len(hits))
+	}
+	if hits := search("john vitae"); len(hits) != 1 || hits[0].ID != "cv" {
+		t.Errorf("Search(john vitae) = %d hits, want cv", len(hits))
+	}
+	if hits := search("hiring"); len(hits) != 1 || hits[0].ID != "letter" {
+		t.Errorf("Search(hiring) = %d hits, want letter", len(hits))
+	}
+	if hits := search("nobody"); len(hits) != 0 {
+		t.Errorf("Search(nobody) = %d hits, want 0", len(hits))
+	}
+}
+
+func testAdminLookup(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	admin, err := repos.Admins.GetAdminByUsername(ctx, AdminUsername)
+	if err != nil {
+		t.Fatalf("GetAdminByUsername: %v", err)
+	}
+	if admin.Username != AdminUsername || admin.PasswordHash != AdminPasswordHash {
+		t.Errorf("GetAdminByUsername = %+v", admin)
+	}
+
+	if _, err := repos.Admins.GetAdminByUsername(ctx, "nobody"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAdminByUsername(nobody) = %v, want ErrNotFound", err)
+	}
+}
+
+func testAdminWrites(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	admin := &domain.Admin{Username: "second-admin", PasswordHash: "first-hash"}
+	if err := repos.Admins.CreateAdmin(ctx, admin); err != nil {
+		t.Fatalf("CreateAdmin: %v", err)
+	}
+	if err := repos.Admins.CreateAdmin(ctx, admin); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateAdmin(duplicate) = %v, want ErrConflict", err)
+	}
+
+	if err := repos.Admins.UpdatePassword(ctx, "second-admin", "second-hash"); err != nil {
+		t.Fatalf("UpdatePassword: %v", err)
+	}
+	got, err := repos.Admins.GetAdminByUsername(ctx, "second-admin")
+	if err != nil {
+		t.Fatalf("GetAdminByUsername: %v", err)
+	}
+	if got.PasswordHash != "second-hash" {
+		t.Errorf("PasswordHash = %q, want second-hash", got.PasswordHash)
+	}
+	if err := repos.Admins.UpdatePassword(ctx, "nobody", "hash"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdatePassword(nobody) = %v, want ErrNotFound", err)
+	}
+
+	if err := repos.Admins.SetDisabled(ctx, "second-admin", true); err != nil {
+		t.Fatalf("SetDisabled: %v", err)
+	}
+	if got, err := repos.Admins.GetAdminByUsername(ctx, "second-admin"); err != nil || !got.Disabled {
+		t.Errorf("GetAdminByUsername after SetDisabled = %+v, %v; want disabled", got, err)
+	}
+	if err := repos.Admins.SetDisabled(ctx, "nobody", true); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SetDisabled(nobody) = %v, want ErrNotFound", err)
+	}
+
+	// Other admins are untouched
+	if got, err := repos.Admins.GetAdminByUsername(ctx, AdminUsername); err != nil || got.PasswordHash != AdminPasswordHash || got.Disabled {
+		t.Errorf("GetAdminByUsername(%s) = %+v, %v", AdminUsername, got, err)
+	}
+
+	admins, err := repos.Admins.ListAdmins(ctx)
+	if err != nil {
+		t.Fatalf("ListAdmins: %v", err)
+	}
+	if len(admins) != 2 || admins[0].Username != AdminUsername || admins[1].Username != "second-admin" {
+		t.Fatalf("ListAdmins = %+v, want [%s second-admin]", admins, AdminUsername)
+	}
+	if admins[0].Disabled || !admins[1].Disabled {
+		t.Errorf("ListAdmins disabled = %v, %v; want false, true", admins[0].Disabled, admins[1].Disabled)
+	}
+	if admins[1].CreatedAt.IsZero() {
+		t.Error("ListAdmins CreatedAt is zero")
+	}
+}
+
+func testSettings(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	if _, err := repos.Settings.GetSettings(ctx); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetSettings before saving = %v, want ErrNotFound", err)
+	}
+	history, err := repos.Settings.ListSettingsHistory(ctx, 10)
+	if err != nil || len(history) != 0 {
+		t.Errorf("ListSettingsHistory before saving = %d versions, %v; want none", len(history), err)
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for v := int64(1); v <= 3; v++ {
+		version := &domain.SettingsVersion{
+			Version: v,
+			Settings: domain.Settings{
+				ShopName:          fmt.Sprintf("Shop %d", v),
+				MaxFileSize:       v << 20,
+				AllowedExtensions: []string{"pdf", "png"},
+				Currency:          "EUR",
+				PriceList:         []domain.Price{{Item: "A4 page", Cents: 10 * v}},
+				OpeningHours:      []domain.OpeningHours{{Day: "monday", Open: "09:00", Close: "17:30"}},
+			},
+			ChangedBy: AdminUsername,
+			ChangedAt: base.Add(time.Duration(v) * time.Minute),
+		}
+		if err := repos.Settings.SaveSettings(ctx, version); err != nil {
+			t.Fatalf("SaveSettings(%d): %v", v, err)
+		}
+	}
+	duplicate := &domain.SettingsVersion{Version: 2, Settings: domain.Settings{ShopName: "Late"}, ChangedBy: AdminUsername, ChangedAt: time.Now()}
+	if err := repos.Settings.SaveSettings(ctx, duplicate); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("SaveSettings(duplicate version) = %v, want ErrConflict", err)
+	}
+
+	current, err := repos.Settings.GetSettings(ctx)
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if current.Version != 3 || current.Settings.ShopName != "Shop 3" || current.ChangedBy != AdminUsername {
+		t.Errorf("GetSettings = %+v, want version 3", current)
+	}
+	if !current.ChangedAt.Equal(base.Add(3 * time.Minute)) {
+		t.Errorf("ChangedAt = %v, want %v", current.ChangedAt, base.Add(3*time.Minute))
+	}
+	settings := current.Settings
+	if settings.MaxFileSize != 3<<20 || len(settings.AllowedExtensions) != 2 || settings.Currency != "EUR" ||
+		len(settings.PriceList) != 1 || settings.PriceList[0].Cents != 30 ||
+		len(settings.OpeningHours) != 1 || settings.OpeningHours[0].Close != "17:30" {
+		t.Errorf("GetSettings settings = %+v", settings)
+	}
+
+	history, err = repos.Settings.ListSettingsHistory(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListSettingsHistory: %v", err)
+	}
+	if len(history) != 2 || history[0].Version != 3 || history[1].Version != 2 {
+		t.Errorf("ListSettingsHistory(2) returned %d versions, want 3 then 2", len(history))
+	}
+}
+
+func mustCreateFolder(t *testing.T, repos Repositories, id string, createdAt time.Time) *domain.Folder {
+	t.Helper()
+	folder := &domain.Folder{ID: id, Name: "Folder " + id, CreatedAt: createdAt}
+	if err := repos.Folders.CreateFolder(context.Background(), folder); err != nil {
+		t.Fatalf("CreateFolder(%s): %v", id, err)
+	}
+	return folder
+}
+
+func mustSaveFile(t *testing.T, repos Repositories, id string, folder *domain.Folder, uploadedAt time.Time) {
+	t.Helper()
+	if err := repos.Files.SaveFile(context.Background(), newFile(id, folder, uploadedAt)); err != nil {
+		t.Fatalf("SaveFile(%s): %v", id, err)
+	}
+}
+
+func newFile(id string, folder *domain.Folder, uploadedAt time.Time) *domain.UploadedFile {
+	return &domain.UploadedFile{
+		ID:         id,
+		FolderID:   folder.ID,
+		FolderName: folder.Name,
+		FileName:   id + ".pdf",
+		FileSize:   100,
+		FileType:   "pdf",
+		FilePath:   "/uploads/" + folder.ID + "/" + id + ".pdf",
+		UploadedAt: uploadedAt,
+	}
+}
+
+func assertTotals(t *testing.T, repos Repositories, folderID string, files int, bytes int64, pages int) {
+	t.Helper()
+	got, err := repos.Folders.GetFolder(context.Background(), folderID)
+	if err != nil {
+		t.Fatalf("GetFolder(%s): %v", folderID, err)
+	}
+	if got.FileCount != files || got.TotalBytes != bytes || got.TotalPages != pages {
+		t.Errorf("GetFolder(%s) totals = %d files, %d bytes, %d pages, want %d, %d, %d",
+			folderID, got.FileCount, got.TotalBytes, got.TotalPages, files, bytes, pages)
+	}
+}
+
+func assertFileIDs(t *testing.T, what string, files []*domain.UploadedFile, want ...string) {
+	t.Helper()
+	got := make([]string, 0, len(files))
+	for _, file := range files {
+		got = append(got, file.ID)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/domain"
+	"time"
+)
+
+// AdminRepository implements domain.AdminRepository using SQLite
+type AdminRepository struct {
+	db querier // SQLite database handle or transaction
+}
+
+// adminSelect selects admins; columns match scanAdmin
+const adminSelect = `SELECT username, password_hash, created_at, disabled FROM admins`
+
+// scanAdmin reads one row produced by adminSelect
+func scanAdmin(row scanner) (*domain.Admin, error) {
+	admin := &domain.Admin{}
+	if err := row.Scan(&admin.Username, &admin.PasswordHash, &admin.CreatedAt, &admin.Disabled); err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// NewAdminRepository creates a new SQLite-backed admin repository
+func NewAdminRepository(db *sql.DB) *AdminRepository {
+	return &AdminRepository{
+		db: db,
+	}
+}
+
+// GetAdminByUsername retrieves admin credentials, returning domain.ErrNotFound if not found
+func (r *AdminRepository) GetAdminByUsername(ctx context.Context, username string) (*domain.Admin, error) {
+	admin, err := scanAdmin(r.db.QueryRowContext(ctx, adminSelect+` WHERE username = ?`, username))
+	if err != nil {
+		return nil, translateError(err, "admin")
+	}
+	return admin, nil
+}
+
+// ListAdmins retrieves every admin account, ordered by username
+func (r *AdminRepository) ListAdmins(ctx context.Context) ([]*domain.Admin, error) {
+	rows, err := r.db.QueryContext(ctx, adminSelect+` ORDER BY username`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := make([]*domain.Admin, 0)
+	for rows.Next() {
+		admin, err := scanAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, admin)
+	}
+	return admins, rows.Err()
+}
+
+// CreateAdmin creates an admin user, returning domain.ErrConflict if the username is taken
+func (r *AdminRepository) CreateAdmin(ctx context.Context, admin *domain.Admin) error {
+	if admin.CreatedAt.IsZero() {
+		admin.CreatedAt = time.Now()
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO admins (username, password_hash, created_at) VALUES (?, ?, ?)`,
+		admin.Username,
+		admin.PasswordHash,
+		admin.CreatedAt.UTC(),
+	)
+	return translateError(err, "admin")
+}
+
+// UpdatePassword replaces an admin's password hash, returning domain.ErrNotFound if not found
+func (r *AdminRepository) UpdatePassword(ctx context.Context, username, passwordHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE admins SET password_hash = ? WHERE username = ?`,
+		passwordHash,
+		username,
+	)
+	if err != nil {
+		return err
+	}
+	return expectRows(result, "admin")
+}
+
+// SetDisabled disables or re-enables an admin, returning domain.ErrNotFound if not found
+func (r *AdminRepository) SetDisabled(ctx context.Context, username string, disabled bool) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE admins SET disabled = ? WHERE username = ?`,
+		disabled,
+		username,
+	)
+	if err != nil {
+		return err
+	}
+	return expectRows(result, "admin")
+}
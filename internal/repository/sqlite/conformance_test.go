@@ -0,0 +1,35 @@
+package sqlite_test
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/repository/repotest"
+	"fileprintapp/internal/repository/sqlite"
+	"path/filepath"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db, err := sqlite.Open(filepath.Join(t.TempDir(), "conformance.db"))
+		if err != nil {
+			t.Fatalf("opening database: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		admins := sqlite.NewAdminRepository(db)
+		admin := &domain.Admin{Username: repotest.AdminUsername, PasswordHash: repotest.AdminPasswordHash}
+		if err := admins.CreateAdmin(context.Background(), admin); err != nil {
+			t.Fatalf("seeding admin: %v", err)
+		}
+
+		return repotest.Repositories{
+			Files:      sqlite.NewFileRepository(db),
+			Folders:    sqlite.NewFolderRepository(db),
+			Admins:     admins,
+			UnitOfWork: sqlite.NewUnitOfWork(db),
+			Search:     sqlite.NewSearchRepository(db),
+			Settings:   sqlite.NewSettingsRepository(db),
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// Package sqlite implements the domain repositories on an embedded SQLite
+// database, for shops that run on a single machine without PostgreSQL.
+// Queries and error semantics match the postgres package (domain.ErrNotFound
+// for missing rows, cascading folder deletes).
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fileprintapp/internal/tracing"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	_ "modernc.org/sqlite" // Pure Go SQLite driver (no cgo)
+)
+
+//go:embed schema.sql
+var schema string
+
+// Open opens (creating if needed) the SQLite database at path and applies the schema
+// Parameters:
+//   - path: Database file location (e.g., "./data/ikonprintzz.db")
+// Returns:
+//   - *sql.DB: Ready-to-use database handle
+//   - error: nil on success, error if the file can't be opened or migrated
+func Open(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	// Foreign keys are off by default in SQLite and must be enabled per connection;
+	// WAL lets readers continue while an upload is being written
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path)
+	db, err := tracing.OpenDB("sqlite", dsn, semconv.DBSystemSqlite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY on
+	// concurrent transactions at the cost of serializing queries
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(context.Background(), schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply schema: %w", err)
+	}
+	if err := upgradeSchema(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply schema: %w", err)
+	}
+
+	return db, nil
+}
+
+// addedColumns lists columns introduced after a table was first created
+// CREATE TABLE IF NOT EXISTS leaves existing tables alone, so these are
+// added to databases that predate them
+var addedColumns = []struct {
+	table, column, definition string
+}{
+	{"uploaded_files", "content_text", "TEXT NOT NULL DEFAULT ''"},
+	{"uploaded_files", "page_count", "INTEGER NOT NULL DEFAULT 0"},
+	{"admins", "disabled", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// droppedColumns lists columns removed from the schema, dropped from
+// databases that still have them
+var droppedColumns = []struct {
+	table, column string
+}{
+	{"folders", "file_count"}, // Replaced by totals computed from uploaded_files
+}
+
+// upgradeSchema brings an existing database up to date with addedColumns and droppedColumns
+func upgradeSchema(ctx context.Context, db *sql.DB) error {
+	for _, c := range addedColumns {
+		exists, err := hasColumn(ctx, db, c.table, c.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	for _, c := range droppedColumns {
+		exists, err := hasColumn(ctx, db, c.table, c.column)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", c.table, c.column)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	var exists bool
+	err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?`, table, column,
+	).Scan(&exists)
+	return exists, err
+}
+
+// querier is the subset of *sql.DB and *sql.Tx used by the repositories
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
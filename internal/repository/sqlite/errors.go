@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fileprintapp/internal/domain"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// translateError converts driver errors into domain errors, naming the
+// entity involved in client-facing messages
+func translateError(err error, what string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewError(domain.ErrNotFound, what+" not found")
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+			return domain.NewError(domain.ErrConflict, what+" already exists")
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			// The only foreign key is uploaded_files.folder_id
+			return domain.NewError(domain.ErrNotFound, "folder not found")
+		}
+	}
+	return err
+}
+
+// expectRows returns a not-found error if a statement affected nothing
+func expectRows(result sql.Result, what string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return translateError(sql.ErrNoRows, what)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/domain"
+	"strings"
+	"time"
+)
+
+// fileColumns lists uploaded_files columns in the order scanFile expects
+const fileColumns = `id, folder_id, folder_name, file_name, file_size, file_type, file_path, uploaded_at, page_count`
+
+// FileRepository implements domain.FileRepository using SQLite
+type FileRepository struct {
+	db querier // SQLite database handle or transaction
+}
+
+// NewFileRepository creates a new SQLite-backed file repository
+func NewFileRepository(db *sql.DB) *FileRepository {
+	return &FileRepository{
+		db: db,
+	}
+}
+
+// SaveFile persists file metadata
+func (r *FileRepository) SaveFile(ctx context.Context, file *domain.UploadedFile) error {
+	if file.UploadedAt.IsZero() {
+		file.UploadedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO uploaded_files (`+fileColumns+`, content_text) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		file.ID,
+		file.FolderID,
+		file.FolderName,
+		file.FileName,
+		file.FileSize,
+		file.FileType,
+		file.FilePath,
+		file.UploadedAt.UTC(),
+		file.PageCount,
+		file.Text,
+	)
+	return translateError(err, "file")
+}
+
+// GetFile retrieves a file by ID, returning domain.ErrNotFound if it doesn't exist
+func (r *FileRepository) GetFile(ctx context.Context, id string) (*domain.UploadedFile, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+fileColumns+` FROM uploaded_files WHERE id = ?`, id)
+	file, err := scanFile(row)
+	if err != nil {
+		return nil, translateError(err, "file")
+	}
+	return file, nil
+}
+
+// GetFilesByFolder retrieves all files in a folder, newest first
+func (r *FileRepository) GetFilesByFolder(ctx context.Context, folderID string) ([]*domain.UploadedFile, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+fileColumns+` FROM uploaded_files WHERE folder_id = ? ORDER BY uploaded_at DESC`,
+		folderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanFiles(rows)
+}
+
+// GetFilesByFolders retrieves the files of several folders, keyed by folder ID
+// and newest first
+func (r *FileRepository) GetFilesByFolders(ctx context.Context, folderIDs []string) (map[string][]*domain.UploadedFile, error) {
+	byFolder := make(map[string][]*domain.UploadedFile)
+	if len(folderIDs) == 0 {
+		return byFolder, nil
+	}
+
+	args := make([]interface{}, len(folderIDs))
+	for i, id := range folderIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(folderIDs)), ", ")
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+fileColumns+` FROM uploaded_files WHERE folder_id IN (`+placeholders+`) ORDER BY uploaded_at DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	files, err := scanFiles(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		byFolder[file.FolderID] = append(byFolder[file.FolderID], file)
+	}
+	return byFolder, nil
+}
+
+// GetAllFiles retrieves all files, newest first
+func (r *FileRepository) GetAllFiles(ctx context.Context) ([]*domain.UploadedFile, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+fileColumns+` FROM uploaded_files ORDER BY uploaded_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanFiles(rows)
+}
+
+// DeleteFile removes file metadata, returning domain.ErrNotFound if it doesn't exist
+func (r *FileRepository) DeleteFile(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM uploaded_files WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return expectRows(result, "file")
+}
+
+// UpdateFolderName updates the denormalized folder name on every file in a folder
+func (r *FileRepository) UpdateFolderName(ctx context.Context, folderID, name string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE uploaded_files SET folder_name = ? WHERE folder_id = ?`, name, folderID)
+	return err
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanFile(row scanner) (*domain.UploadedFile, error) {
+	file := &domain.UploadedFile{}
+	err := row.Scan(
+		&file.ID,
+		&file.FolderID,
+		&file.FolderName,
+		&file.FileName,
+		&file.FileSize,
+		&file.FileType,
+		&file.FilePath,
+		&file.UploadedAt,
+		&file.PageCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func scanFiles(rows *sql.Rows) ([]*domain.UploadedFile, error) {
+	defer rows.Close()
+
+	files := make([]*domain.UploadedFile, 0)
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/domain"
+	"time"
+)
+
+// folderSelect selects folders with totals derived from their files, in the
+// order scanFolder expects; counts are computed so they can't drift
+const folderSelect = `SELECT id, name, created_at,
+	(SELECT COUNT(*) FROM uploaded_files u WHERE u.folder_id = folders.id),
+	(SELECT COALESCE(SUM(u.file_size), 0) FROM uploaded_files u WHERE u.folder_id = folders.id),
+	(SELECT COALESCE(SUM(u.page_count), 0) FROM uploaded_files u WHERE u.folder_id = folders.id)
+	FROM folders`
+
+// FolderRepository implements domain.FolderRepository using SQLite
+type FolderRepository struct {
+	db querier // SQLite database handle or transaction
+}
+
+// NewFolderRepository creates a new SQLite-backed folder repository
+func NewFolderRepository(db *sql.DB) *FolderRepository {
+	return &FolderRepository{
+		db: db,
+	}
+}
+
+// CreateFolder persists a new folder; creating an existing ID is a no-op
+func (r *FolderRepository) CreateFolder(ctx context.Context, folder *domain.Folder) error {
+	if folder.CreatedAt.IsZero() {
+		folder.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO folders (id, name, created_at) VALUES (?, ?, ?) ON CONFLICT (id) DO NOTHING`,
+		folder.ID,
+		folder.Name,
+		folder.CreatedAt.UTC(),
+	)
+	return translateError(err, "folder")
+}
+
+// GetFolder retrieves a folder by ID, returning domain.ErrNotFound if it doesn't exist
+func (r *FolderRepository) GetFolder(ctx context.Context, id string) (*domain.Folder, error) {
+	folder, err := scanFolder(r.db.QueryRowContext(ctx, folderSelect+` WHERE id = ?`, id))
+	if err != nil {
+		return nil, translateError(err, "folder")
+	}
+	return folder, nil
+}
+
+// GetAllFolders retrieves all folders, newest first
+func (r *FolderRepository) GetAllFolders(ctx context.Context) ([]*domain.Folder, error) {
+	rows, err := r.db.QueryContext(ctx, folderSelect+` ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanFolders(rows)
+}
+
+// RenameFolder changes a folder's name, returning domain.ErrNotFound if it doesn't exist
+func (r *FolderRepository) RenameFolder(ctx context.Context, id, name string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE folders SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return err
+	}
+	return expectRows(result, "folder")
+}
+
+// DeleteFolder removes a folder and (by cascade) its file rows,
+// returning domain.ErrNotFound if it doesn't exist
+func (r *FolderRepository) DeleteFolder(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM folders WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return expectRows(result, "folder")
+}
+
+func scanFolder(row scanner) (*domain.Folder, error) {
+	folder := &domain.Folder{}
+	err := row.Scan(
+		&folder.ID,
+		&folder.Name,
+		&folder.CreatedAt,
+		&folder.FileCount,
+		&folder.TotalBytes,
+		&folder.TotalPages,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func scanFolders(rows *sql.Rows) ([]*domain.Folder, error) {
+	defer rows.Close()
+
+	folders := make([]*domain.Folder, 0)
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+	return folders, rows.Err()
+}
@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fileSortColumns maps sort fields to uploaded_files columns
+var fileSortColumns = map[domain.SortField]string{
+	domain.SortByDate: "uploaded_at",
+	domain.SortByName: "file_name",
+	domain.SortBySize: "file_size",
+}
+
+// folderSortColumns maps sort fields to folders columns
+var folderSortColumns = map[domain.SortField]string{
+	domain.SortByDate: "created_at",
+	domain.SortByName: "name",
+}
+
+// listQuery accumulates WHERE conditions and their arguments
+type listQuery struct {
+	where []string
+	args  []interface{}
+}
+
+// add appends a condition with its arguments
+func (q *listQuery) add(cond string, args ...interface{}) {
+	q.where = append(q.where, cond)
+	q.args = append(q.args, args...)
+}
+
+// between restricts column to [from, to), ignoring zero bounds
+// Times are stored in UTC, so bounds are converted before comparing
+func (q *listQuery) between(column string, from, to time.Time) {
+	if !from.IsZero() {
+		q.add(column+" >= ?", from.UTC())
+	}
+	if !to.IsZero() {
+		q.add(column+" < ?", to.UTC())
+	}
+}
+
+// after applies keyset pagination: rows strictly after the cursor in sort order
+func (q *listQuery) after(column, cursor string, order domain.Sort) error {
+	if cursor == "" {
+		return nil
+	}
+	c, err := domain.DecodeCursor(cursor, order)
+	if err != nil {
+		return err
+	}
+	value, err := domain.CursorArg(c, order.Field)
+	if err != nil {
+		return err
+	}
+	if t, ok := value.(time.Time); ok {
+		value = t.UTC()
+	}
+
+	op := "<"
+	if order.Ascending {
+		op = ">"
+	}
+	q.add(fmt.Sprintf("(%s, id) %s (?, ?)", column, op), value, c.ID)
+	return nil
+}
+
+// build renders the final statement, fetching one extra row to detect a next page
+func (q *listQuery) build(selectFrom, column string, order domain.Sort, limit int) string {
+	dir := "DESC"
+	if order.Ascending {
+		dir = "ASC"
+	}
+
+	stmt := selectFrom
+	if len(q.where) > 0 {
+		stmt += " WHERE " + strings.Join(q.where, " AND ")
+	}
+	q.args = append(q.args, limit+1)
+	return stmt + fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT ?", column, dir, dir)
+}
+
+// likePattern escapes LIKE wildcards in a search term (used with ESCAPE '\')
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s) + "%"
+}
+
+// ListFiles retrieves one page of files matching the query using keyset pagination
+func (r *FileRepository) ListFiles(ctx context.Context, query domain.FileQuery) (*domain.FilePage, error) {
+	order := query.Sort
+	if order.Field == "" {
+		order = domain.DefaultSort
+	}
+	column := fileSortColumns[order.Field]
+	limit := domain.PageSize(query.Limit)
+
+	q := &listQuery{}
+	if query.FolderID != "" {
+		q.add("folder_id = ?", query.FolderID)
+	}
+	if query.FileType != "" {
+		q.add("file_type = ?", query.FileType)
+	}
+	q.between("uploaded_at", query.From, query.To)
+	if query.Search != "" {
+		p := likePattern(query.Search)
+		q.add(`(file_name LIKE ? ESCAPE '\' OR folder_name LIKE ? ESCAPE '\')`, p, p)
+	}
+	if err := q.after(column, query.Cursor, order); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, q.build(`SELECT `+fileColumns+` FROM uploaded_files`, column, order, limit), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	files, err := scanFiles(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.FilePage{Files: files}
+	if len(files) > limit {
+		page.Files = files[:limit]
+		last := page.Files[limit-1]
+		page.NextCursor = domain.EncodeCursor(order, domain.FileSortValue(last, order.Field), last.ID)
+	}
+	return page, nil
+}
+
+// ListFolders retrieves one page of folders matching the query using keyset pagination
+func (r *FolderRepository) ListFolders(ctx context.Context, query domain.FolderQuery) (*domain.FolderPage, error) {
+	order := query.Sort
+	if order.Field == "" {
+		order = domain.DefaultSort
+	}
+	column := folderSortColumns[order.Field]
+	limit := domain.PageSize(query.Limit)
+
+	q := &listQuery{}
+	q.between("created_at", query.From, query.To)
+	if query.Search != "" {
+		q.add(`name LIKE ? ESCAPE '\'`, likePattern(query.Search))
+	}
+	if err := q.after(column, query.Cursor, order); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, q.build(folderSelect, column, order, limit), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := scanFolders(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.FolderPage{Folders: folders}
+	if len(folders) > limit {
+		page.Folders = folders[:limit]
+		last := page.Folders[limit-1]
+		page.NextCursor = domain.EncodeCursor(order, domain.FolderSortValue(last, order.Field), last.ID)
+	}
+	return page, nil
+}
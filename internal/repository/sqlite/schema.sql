@@ -0,0 +1,51 @@
+-- SQLite schema for File Print Service
+-- Mirrors migrations/ (PostgreSQL) for single-box installs
+-- Applied on every startup; every statement must be idempotent
+-- Columns added or dropped later are also listed in database.go
+
+CREATE TABLE IF NOT EXISTS folders (
+    id TEXT PRIMARY KEY,                              -- UUID generated in application
+    name TEXT NOT NULL,                               -- User-defined folder name
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_folders_created_at ON folders(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS uploaded_files (
+    id TEXT PRIMARY KEY,                              -- UUID generated in application
+    folder_id TEXT NOT NULL REFERENCES folders(id) ON DELETE CASCADE,
+    folder_name TEXT NOT NULL,                        -- Denormalized for quick display
+    file_name TEXT NOT NULL,                          -- Original filename from user
+    file_size INTEGER NOT NULL,                       -- Size in bytes
+    file_type TEXT NOT NULL,                          -- Extension (pdf, jpg, png, etc.)
+    file_path TEXT NOT NULL,                          -- Path to physical file
+    uploaded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    page_count INTEGER NOT NULL DEFAULT 0,            -- PDF pages (1 for images)
+    content_text TEXT NOT NULL DEFAULT ''             -- Extracted PDF text for search
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_folder_id ON uploaded_files(folder_id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_uploaded_at ON uploaded_files(uploaded_at DESC);
+
+CREATE TABLE IF NOT EXISTS admins (
+    username TEXT PRIMARY KEY,                        -- Admin username (unique)
+    password_hash TEXT NOT NULL,                      -- Bcrypt hashed password
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    disabled INTEGER NOT NULL DEFAULT 0               -- Disabled admins can't log in
+);
+
+-- Shop settings; every saved version is kept (mirrors migrations/006_settings.up.sql)
+CREATE TABLE IF NOT EXISTS settings (
+    version INTEGER PRIMARY KEY,                      -- 1, 2, 3, ... in order of saving
+    settings TEXT NOT NULL,                           -- domain.Settings as JSON
+    changed_by TEXT NOT NULL,                         -- Admin who saved this version
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Listing indexes (mirrors migrations/002_listing_indexes.up.sql)
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_uploaded_at_id ON uploaded_files(uploaded_at, id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_file_name_id ON uploaded_files(file_name, id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_file_size_id ON uploaded_files(file_size, id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_folder_id_uploaded_at ON uploaded_files(folder_id, uploaded_at, id);
+CREATE INDEX IF NOT EXISTS idx_folders_created_at_id ON folders(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_folders_name_id ON folders(name, id);
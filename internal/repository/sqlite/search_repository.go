@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/domain"
+	"strings"
+)
+
+// SearchRepository implements domain.SearchRepository on SQLite
+// Candidate rows are narrowed with LIKE and ranked in Go with domain.Rank,
+// matching the memory repository's results
+type SearchRepository struct {
+	db querier // SQLite database handle or transaction
+}
+
+// NewSearchRepository creates a new SQLite-backed search repository
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{
+		db: db,
+	}
+}
+
+// Search finds folders and files matching every term, best first
+func (r *SearchRepository) Search(ctx context.Context, terms []string, limit int) ([]*domain.SearchHit, error) {
+	hits := make([]*domain.SearchHit, 0)
+	if len(terms) == 0 {
+		return hits, nil
+	}
+
+	folders, err := r.searchFolders(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
+	files, err := r.searchFiles(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
+	hits = append(append(hits, folders...), files...)
+
+	domain.SortSearchHits(hits)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func (r *SearchRepository) searchFolders(ctx context.Context, terms []string) ([]*domain.SearchHit, error) {
+	where, args := likeAll(terms, "name")
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name FROM folders WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*domain.SearchHit
+	for rows.Next() {
+		hit := &domain.SearchHit{Kind: domain.SearchKindFolder}
+		if err := rows.Scan(&hit.ID, &hit.FolderName); err != nil {
+			return nil, err
+		}
+		hit.FolderID = hit.ID
+		hit.Rank = domain.Rank(terms, domain.RankField{Text: hit.FolderName, Weight: domain.RankWeightName})
+		if hit.Rank > 0 {
+			hits = append(hits, hit)
+		}
+	}
+	return hits, rows.Err()
+}
+
+func (r *SearchRepository) searchFiles(ctx context.Context, terms []string) ([]*domain.SearchHit, error) {
+	where, args := likeAll(terms, "file_name", "folder_name", "content_text")
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, folder_id, folder_name, file_name, content_text FROM uploaded_files WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*domain.SearchHit
+	for rows.Next() {
+		hit := &domain.SearchHit{Kind: domain.SearchKindFile}
+		var text string
+		if err := rows.Scan(&hit.ID, &hit.FolderID, &hit.FolderName, &hit.FileName, &text); err != nil {
+			return nil, err
+		}
+		hit.Rank = domain.Rank(terms,
+			domain.RankField{Text: hit.FileName, Weight: domain.RankWeightName},
+			domain.RankField{Text: hit.FolderName, Weight: domain.RankWeightFolder},
+			domain.RankField{Text: text, Weight: domain.RankWeightContent},
+		)
+		if hit.Rank > 0 {
+			hits = append(hits, hit)
+		}
+	}
+	return hits, rows.Err()
+}
+
+// likeAll requires every term to appear in at least one of columns
+func likeAll(terms []string, columns ...string) (string, []interface{}) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	for _, term := range terms {
+		ors := make([]string, len(columns))
+		for i, column := range columns {
+			ors[i] = column + ` LIKE ? ESCAPE '\'`
+			args = append(args, likePattern(term))
+		}
+		conds = append(conds, "("+strings.Join(ors, " OR ")+")")
+	}
+	return strings.Join(conds, " AND "), args
+}
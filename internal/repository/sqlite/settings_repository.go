@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fileprintapp/internal/domain"
+)
+
+// SettingsRepository implements domain.SettingsRepository using SQLite
+type SettingsRepository struct {
+	db querier // SQLite database handle or transaction
+}
+
+// settingsSelect selects settings versions; columns match scanSettingsVersion
+const settingsSelect = `SELECT version, settings, changed_by, changed_at FROM settings`
+
+// scanSettingsVersion reads one row produced by settingsSelect
+func scanSettingsVersion(row scanner) (*domain.SettingsVersion, error) {
+	version := &domain.SettingsVersion{}
+	var settings string
+	if err := row.Scan(&version.Version, &settings, &version.ChangedBy, &version.ChangedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(settings), &version.Settings); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// NewSettingsRepository creates a new SQLite-backed settings repository
+func NewSettingsRepository(db *sql.DB) *SettingsRepository {
+	return &SettingsRepository{
+		db: db,
+	}
+}
+
+// GetSettings retrieves the newest settings version, returning domain.ErrNotFound if none was saved
+func (r *SettingsRepository) GetSettings(ctx context.Context) (*domain.SettingsVersion, error) {
+	version, err := scanSettingsVersion(r.db.QueryRowContext(ctx, settingsSelect+` ORDER BY version DESC LIMIT 1`))
+	if err != nil {
+		return nil, translateError(err, "settings")
+	}
+	return version, nil
+}
+
+// SaveSettings stores a new settings version, returning domain.ErrConflict if the version number is taken
+func (r *SettingsRepository) SaveSettings(ctx context.Context, version *domain.SettingsVersion) error {
+	settings, err := json.Marshal(version.Settings)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO settings (version, settings, changed_by, changed_at) VALUES (?, ?, ?, ?)`,
+		version.Version,
+		string(settings),
+		version.ChangedBy,
+		version.ChangedAt.UTC(),
+	)
+	return translateError(err, "settings version")
+}
+
+// ListSettingsHistory retrieves settings versions, newest first
+func (r *SettingsRepository) ListSettingsHistory(ctx context.Context, limit int) ([]*domain.SettingsVersion, error) {
+	rows, err := r.db.QueryContext(ctx, settingsSelect+` ORDER BY version DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]*domain.SettingsVersion, 0)
+	for rows.Next() {
+		version, err := scanSettingsVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
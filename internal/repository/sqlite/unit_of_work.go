@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fileprintapp/internal/domain"
+	"fmt"
+)
+
+// UnitOfWork implements domain.UnitOfWork with SQLite transactions
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a transaction runner for the given database
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+// Do runs fn inside a single transaction, committing only if it returns nil
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos domain.Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	repos := domain.Repositories{
+		Files:   &FileRepository{db: tx},
+		Folders: &FolderRepository{db: tx},
+	}
+
+	if err := fn(repos); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
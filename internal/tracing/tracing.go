@@ -0,0 +1,57 @@
+// Package tracing sets up OpenTelemetry tracing
+//
+// Spans are exported over OTLP/HTTP when an endpoint is configured. Without
+// one nothing is recorded, but W3C trace context (traceparent) is still
+// propagated, so a trace ID sent by a proxy reaches the logs.
+//
+// Instrumented code uses the global tracer provider, so tests can capture
+// spans by installing one with an in-memory exporter:
+//
+//	exporter := tracetest.NewInMemoryExporter()
+//	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// serviceName is reported unless OTEL_SERVICE_NAME overrides it
+const serviceName = "ikonprintzz"
+
+// Setup installs the W3C propagators and, when endpoint is set, a tracer
+// provider exporting to it
+// endpoint is the OTLP/HTTP base URL (e.g. http://localhost:4318); spans are
+// sent to its /v1/traces path. The exporter also honours the standard
+// OTEL_EXPORTER_OTLP_HEADERS, and the provider OTEL_TRACES_SAMPLER(_ARG).
+// The returned function flushes buffered spans and must be called on exit.
+func Setup(ctx context.Context, endpoint, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(strings.TrimSuffix(endpoint, "/")+"/v1/traces"))
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName), semconv.ServiceVersion(version)),
+		resource.WithFromEnv(), // OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
@@ -1,14 +1,24 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fileprintapp/internal/domain"
+	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	errInvalidCredentials = domain.NewError(domain.ErrUnauthorized, "invalid credentials")
+	errInvalidToken       = domain.NewError(domain.ErrUnauthorized, "invalid token")
+)
+
 // AuthService handles authentication logic
 type AuthService struct {
 	adminRepo domain.AdminRepository
@@ -24,15 +34,27 @@ func NewAuthService(adminRepo domain.AdminRepository, jwtSecret string) *AuthSer
 }
 
 // Login authenticates an admin and returns a JWT token
-func (s *AuthService) Login(username, password string) (string, error) {
-	admin, err := s.adminRepo.GetAdminByUsername(username)
+func (s *AuthService) Login(ctx context.Context, username, password string) (_ string, err error) {
+	ctx, end := startSpan(ctx, "AuthService.Login", attribute.String("admin.username", username))
+	defer end(&err)
+
+	admin, err := s.adminRepo.GetAdminByUsername(ctx, username)
+	if errors.Is(err, domain.ErrNotFound) {
+		slog.WarnContext(ctx, "admin login failed", "username", username, "reason", "unknown admin")
+		return "", errInvalidCredentials
+	}
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return "", err
 	}
 
 	// Verify password
+	if admin.Disabled {
+		slog.WarnContext(ctx, "admin login failed", "username", username, "reason", "disabled")
+		return "", errInvalidCredentials
+	}
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(password)); err != nil {
-		return "", errors.New("invalid credentials")
+		slog.WarnContext(ctx, "admin login failed", "username", username, "reason", "wrong password")
+		return "", errInvalidCredentials
 	}
 
 	// Generate JWT token
@@ -46,11 +68,17 @@ func (s *AuthService) Login(username, password string) (string, error) {
 		return "", err
 	}
 
+	slog.InfoContext(ctx, "admin logged in", "username", admin.Username)
 	return tokenString, nil
 }
 
-// ValidateToken validates a JWT token
-func (s *AuthService) ValidateToken(tokenString string) (string, error) {
+// ValidateToken validates a JWT token and returns the admin it was issued to
+// Tokens of admins that have since been disabled or removed are rejected.
+// Every failure is reported as domain.ErrUnauthorized
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (_ string, err error) {
+	ctx, end := startSpan(ctx, "AuthService.ValidateToken")
+	defer end(&err)
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
@@ -59,15 +87,118 @@ func (s *AuthService) ValidateToken(tokenString string) (string, error) {
 	})
 
 	if err != nil {
-		return "", err
+		return "", errInvalidToken
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		username := claims["username"].(string)
-		return username, nil
+		if username, ok := claims["username"].(string); ok {
+			return s.activeAdmin(ctx, username)
+		}
+	}
+
+	return "", errInvalidToken
+}
+
+// activeAdmin returns username if it names an enabled admin
+func (s *AuthService) activeAdmin(ctx context.Context, username string) (string, error) {
+	admin, err := s.adminRepo.GetAdminByUsername(ctx, username)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", errInvalidToken
+	}
+	if err != nil {
+		return "", err
+	}
+	if admin.Disabled {
+		return "", errInvalidToken
+	}
+	return username, nil
+}
+
+// ListAdmins retrieves every admin account
+func (s *AuthService) ListAdmins(ctx context.Context) (_ []*domain.Admin, err error) {
+	ctx, end := startSpan(ctx, "AuthService.ListAdmins")
+	defer end(&err)
+	return s.adminRepo.ListAdmins(ctx)
+}
+
+// SetDisabled disables or re-enables an admin
+// The last enabled admin can't be disabled, so the dashboard stays reachable
+func (s *AuthService) SetDisabled(ctx context.Context, username string, disabled bool) (err error) {
+	ctx, end := startSpan(ctx, "AuthService.SetDisabled", attribute.String("admin.username", username))
+	defer end(&err)
+
+	if disabled {
+		admins, err := s.adminRepo.ListAdmins(ctx)
+		if err != nil {
+			return err
+		}
+		active := 0
+		for _, admin := range admins {
+			if !admin.Disabled && admin.Username != username {
+				active++
+			}
+		}
+		if active == 0 {
+			return domain.NewError(domain.ErrConflict, "cannot disable the last enabled admin")
+		}
+	}
+	return s.adminRepo.SetDisabled(ctx, username, disabled)
+}
+
+// minPasswordLength is enforced when an admin's password is chosen
+const minPasswordLength = 8
+
+// CreateAdmin adds an admin account
+func (s *AuthService) CreateAdmin(ctx context.Context, username, password string) (err error) {
+	ctx, end := startSpan(ctx, "AuthService.CreateAdmin", attribute.String("admin.username", username))
+	defer end(&err)
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return domain.NewError(domain.ErrValidation, "username is required")
+	}
+	hash, err := hashNewPassword(password)
+	if err != nil {
+		return err
 	}
+	return s.adminRepo.CreateAdmin(ctx, &domain.Admin{Username: username, PasswordHash: hash})
+}
+
+// ResetPassword sets a new password for an existing admin
+func (s *AuthService) ResetPassword(ctx context.Context, username, password string) (err error) {
+	ctx, end := startSpan(ctx, "AuthService.ResetPassword", attribute.String("admin.username", username))
+	defer end(&err)
 
-	return "", errors.New("invalid token")
+	hash, err := hashNewPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.adminRepo.UpdatePassword(ctx, username, hash)
+}
+
+// EnsureAdmin creates the configured admin on first start
+// An existing account is left alone, so a password changed with
+// ResetPassword isn't overwritten by the configured one
+func (s *AuthService) EnsureAdmin(ctx context.Context, username, password string) (err error) {
+	ctx, end := startSpan(ctx, "AuthService.EnsureAdmin", attribute.String("admin.username", username))
+	defer end(&err)
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = s.adminRepo.CreateAdmin(ctx, &domain.Admin{Username: username, PasswordHash: hash})
+	if errors.Is(err, domain.ErrConflict) {
+		return nil
+	}
+	return err
+}
+
+func hashNewPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", domain.NewError(domain.ErrValidation, fmt.Sprintf("password must be at least %d characters", minPasswordLength))
+	}
+	return HashPassword(password)
 }
 
 // HashPassword hashes a password using bcrypt
@@ -1,57 +1,93 @@
 package usecase
 
 import (
-	"errors"
+	"context"
 	"fileprintapp/internal/domain"
+	"fileprintapp/internal/pdftext"
+	"fmt"
 	"io"
-	"mime/multipart"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // FileService handles file-related business logic
 type FileService struct {
-	fileRepo      domain.FileRepository
-	folderRepo    domain.FolderRepository
-	uploadPath    string
-	maxFileSize   int64
-	allowedExtensions []string
+	fileRepo   domain.FileRepository
+	folderRepo domain.FolderRepository
+	uow        domain.UnitOfWork
+	uploadPath string
+	settings   *SettingsService // Upload limits, changeable at runtime
+	quota      FolderQuota
+
+	// In-flight uploads, tracked so shutdown can wait for them
+	uploadsMu     sync.Mutex
+	uploadsClosed bool
+	uploads       sync.WaitGroup
+}
+
+// FolderQuota caps what one folder may hold; zero values mean no limit
+type FolderQuota struct {
+	MaxFiles int64
+	MaxBytes int64
 }
 
 // NewFileService creates a new file service
-func NewFileService(fileRepo domain.FileRepository, folderRepo domain.FolderRepository, uploadPath string, maxFileSize int64, allowedExtensions []string) *FileService {
+func NewFileService(fileRepo domain.FileRepository, folderRepo domain.FolderRepository, uow domain.UnitOfWork, uploadPath string, settings *SettingsService, quota FolderQuota) *FileService {
 	return &FileService{
-		fileRepo:      fileRepo,
-		folderRepo:    folderRepo,
-		uploadPath:    uploadPath,
-		maxFileSize:   maxFileSize,
-		allowedExtensions: allowedExtensions,
+		fileRepo:   fileRepo,
+		folderRepo: folderRepo,
+		uow:        uow,
+		uploadPath: uploadPath,
+		settings:   settings,
+		quota:      quota,
+	}
+}
+
+// MaxUploadSize returns the largest file the settings in effect accept, in bytes
+// Handlers use it to cap how much of a request body they read.
+func (s *FileService) MaxUploadSize(ctx context.Context) (int64, error) {
+	current, err := s.settings.Current(ctx)
+	if err != nil {
+		return 0, err
 	}
+	return current.Settings.MaxFileSize, nil
 }
 
-// UploadFile handles file upload logic
-func (s *FileService) UploadFile(fileHeader *multipart.FileHeader, folderID, folderName string) (*domain.UploadedFile, error) {
-	// Validate file size
-	if fileHeader.Size > s.maxFileSize {
-		return nil, errors.New("file size exceeds maximum allowed size")
+// UploadFile stores the contents of content as fileName in a folder
+// content is streamed straight to disk, so its size is only known once it
+// has been read: a file over the size limit or the folder's byte quota is
+// removed again.
+func (s *FileService) UploadFile(ctx context.Context, folderID, folderName, fileName string, content io.Reader) (_ *domain.UploadedFile, err error) {
+	ctx, end := startSpan(ctx, "FileService.UploadFile", attribute.String("folder.id", folderID))
+	defer end(&err)
+
+	// Validate against the settings in effect now
+	current, err := s.settings.Current(ctx)
+	if err != nil {
+		return nil, err
 	}
+	limits := current.Settings
 
 	// Validate file extension
-	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	ext := strings.ToLower(filepath.Ext(fileName))
 	ext = strings.TrimPrefix(ext, ".")
-	if !s.isAllowedExtension(ext) {
-		return nil, errors.New("file type not allowed")
+	if !limits.AllowsExtension(ext) {
+		return nil, domain.NewError(domain.ErrValidation, "file type not allowed")
 	}
 
-	// Open uploaded file
-	file, err := fileHeader.Open()
-	if err != nil {
+	// Refuse uploads to a missing or full folder before writing anything
+	if err := s.checkQuota(ctx, folderID, 0); err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	// Create folder directory if it doesn't exist
 	folderPath := filepath.Join(s.uploadPath, folderID)
@@ -61,88 +97,264 @@ func (s *FileService) UploadFile(fileHeader *multipart.FileHeader, folderID, fol
 
 	// Generate unique file ID and path
 	fileID := uuid.New().String()
-	fileName := fileHeader.Filename
 	filePath := filepath.Join(folderPath, fileID+filepath.Ext(fileName))
 
-	// Save file to disk
-	dst, err := os.Create(filePath)
+	// Save file to disk, reading one byte past the limit to detect larger
+	// files; a failed, cancelled or refused upload leaves nothing behind
+	size, err := writeFile(ctx, filePath, io.LimitReader(content, limits.MaxFileSize+1))
 	if err != nil {
+		os.Remove(filePath)
 		return nil, err
 	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("file.size", size))
+	if size > limits.MaxFileSize {
+		os.Remove(filePath)
+		return nil, domain.NewError(domain.ErrTooLarge, "file size exceeds maximum allowed size")
+	}
+	if err := s.checkQuota(ctx, folderID, size); err != nil {
+		os.Remove(filePath)
 		return nil, err
 	}
 
+	// Index PDF text for search and count pages; images print on one page
+	// Files that can't be parsed are still accepted
+	text, pages := "", 1
+	if ext == "pdf" {
+		_, span := tracer.Start(ctx, "extract pdf text")
+		pages = 0
+		if info, err := pdftext.ReadFile(filePath); err == nil {
+			text, pages = info.Text, info.Pages
+		} else {
+			span.RecordError(err)
+		}
+		span.SetAttributes(attribute.Int("pdf.pages", pages))
+		span.End()
+	}
+
 	// Create file entity
 	uploadedFile := &domain.UploadedFile{
 		ID:         fileID,
 		FolderID:   folderID,
 		FolderName: folderName,
 		FileName:   fileName,
-		FileSize:   fileHeader.Size,
+		FileSize:   size,
 		FileType:   ext,
 		FilePath:   filePath,
+		UploadedAt: time.Now(),
+		PageCount:  pages,
+		Text:       text,
 	}
 
-	// Save to repository
-	if err := s.fileRepo.SaveFile(uploadedFile); err != nil {
+	// Folder totals are derived from the saved record, so nothing else to update
+	if err := s.fileRepo.SaveFile(ctx, uploadedFile); err != nil {
 		os.Remove(filePath) // Clean up file on error
 		return nil, err
 	}
 
-	// Update folder file count
-	files, _ := s.fileRepo.GetFilesByFolder(folderID)
-	s.folderRepo.UpdateFolderFileCount(folderID, len(files))
-
+	slog.InfoContext(ctx, "file uploaded", "file_id", fileID, "folder_id", folderID, "size", size, "type", ext)
 	return uploadedFile, nil
 }
 
+// checkQuota fails with ErrNotFound if the folder doesn't exist, and with
+// ErrLimitExceeded if a file of size would take it over its quota
+// Uploads are checked with size 0 before they are read and again once their
+// size is known. Concurrent uploads to one folder are checked against the same totals, so
+// a folder can overshoot by the uploads in flight; the per-folder rate limit
+// keeps that small.
+func (s *FileService) checkQuota(ctx context.Context, folderID string, size int64) error {
+	folder, err := s.folderRepo.GetFolder(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
+	if s.quota.MaxFiles > 0 && int64(folder.FileCount) >= s.quota.MaxFiles {
+		return domain.NewError(domain.ErrLimitExceeded, fmt.Sprintf("folder already holds the maximum of %d files", s.quota.MaxFiles))
+	}
+	if s.quota.MaxBytes > 0 && folder.TotalBytes+size > s.quota.MaxBytes {
+		return domain.NewError(domain.ErrLimitExceeded, fmt.Sprintf("folder would exceed its limit of %d bytes", s.quota.MaxBytes))
+	}
+	return nil
+}
+
+// writeFile copies an upload to path, syncs it to disk and returns its size
+// On slow connections most of the span is spent waiting for the client.
+func writeFile(ctx context.Context, path string, src io.Reader) (_ int64, err error) {
+	ctx, end := startSpan(ctx, "write file")
+	defer end(&err)
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	size, err := io.Copy(dst, src)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := dst.Sync(); err != nil {
+		return 0, err
+	}
+	return size, dst.Close()
+}
+
+// BeginUpload registers an upload request before its body is read
+// It fails with ErrUnavailable once StopUploads was called; otherwise call
+// the returned function when the request is done
+func (s *FileService) BeginUpload() (func(), error) {
+	s.uploadsMu.Lock()
+	defer s.uploadsMu.Unlock()
+	if s.uploadsClosed {
+		return nil, domain.NewError(domain.ErrUnavailable, "server is shutting down, please retry the upload shortly")
+	}
+	s.uploads.Add(1)
+	return s.uploads.Done, nil
+}
+
+// StopUploads makes BeginUpload refuse new uploads
+// Uploads already in progress carry on; use WaitForUploads to wait for them
+func (s *FileService) StopUploads() {
+	s.uploadsMu.Lock()
+	s.uploadsClosed = true
+	s.uploadsMu.Unlock()
+}
+
+// AcceptingUploads reports whether BeginUpload still accepts uploads
+func (s *FileService) AcceptingUploads() bool {
+	s.uploadsMu.Lock()
+	defer s.uploadsMu.Unlock()
+	return !s.uploadsClosed
+}
+
+// WaitForUploads blocks until every in-flight upload has finished or ctx is done
+func (s *FileService) WaitForUploads(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.uploads.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // GetAllFiles retrieves all uploaded files
-func (s *FileService) GetAllFiles() ([]*domain.UploadedFile, error) {
-	return s.fileRepo.GetAllFiles()
+func (s *FileService) GetAllFiles(ctx context.Context) (_ []*domain.UploadedFile, err error) {
+	ctx, end := startSpan(ctx, "FileService.GetAllFiles")
+	defer end(&err)
+	return s.fileRepo.GetAllFiles(ctx)
+}
+
+// ListFiles retrieves one page of files matching the query
+func (s *FileService) ListFiles(ctx context.Context, query domain.FileQuery) (_ *domain.FilePage, err error) {
+	ctx, end := startSpan(ctx, "FileService.ListFiles")
+	defer end(&err)
+	return s.fileRepo.ListFiles(ctx, query)
 }
 
 // GetFilesByFolder retrieves files by folder ID
-func (s *FileService) GetFilesByFolder(folderID string) ([]*domain.UploadedFile, error) {
-	return s.fileRepo.GetFilesByFolder(folderID)
+func (s *FileService) GetFilesByFolder(ctx context.Context, folderID string) (_ []*domain.UploadedFile, err error) {
+	ctx, end := startSpan(ctx, "FileService.GetFilesByFolder", attribute.String("folder.id", folderID))
+	defer end(&err)
+	return s.fileRepo.GetFilesByFolder(ctx, folderID)
 }
 
-// DeleteFile deletes a file
-func (s *FileService) DeleteFile(fileID string) error {
-	file, err := s.fileRepo.GetFile(fileID)
+// DeleteFile deletes a file and returns the removed record
+// The physical file is only removed once the database change has committed
+func (s *FileService) DeleteFile(ctx context.Context, fileID string) (_ *domain.UploadedFile, err error) {
+	ctx, end := startSpan(ctx, "FileService.DeleteFile", attribute.String("file.id", fileID))
+	defer end(&err)
+
+	var file *domain.UploadedFile
+	err = s.uow.Do(ctx, func(repos domain.Repositories) error {
+		var err error
+		file, err = repos.Files.GetFile(ctx, fileID)
+		if err != nil {
+			return err
+		}
+		return repos.Files.DeleteFile(ctx, fileID)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Delete physical file
 	if err := os.Remove(file.FilePath); err != nil && !os.IsNotExist(err) {
-		return err
-	}
-
-	// Delete from repository
-	if err := s.fileRepo.DeleteFile(fileID); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Update folder file count
-	files, _ := s.fileRepo.GetFilesByFolder(file.FolderID)
-	s.folderRepo.UpdateFolderFileCount(file.FolderID, len(files))
-
-	return nil
+	slog.InfoContext(ctx, "file deleted", "file_id", file.ID, "folder_id", file.FolderID)
+	return file, nil
 }
 
 // GetFile retrieves a file by ID
-func (s *FileService) GetFile(fileID string) (*domain.UploadedFile, error) {
-	return s.fileRepo.GetFile(fileID)
+func (s *FileService) GetFile(ctx context.Context, fileID string) (_ *domain.UploadedFile, err error) {
+	ctx, end := startSpan(ctx, "FileService.GetFile", attribute.String("file.id", fileID))
+	defer end(&err)
+	return s.fileRepo.GetFile(ctx, fileID)
 }
 
-func (s *FileService) isAllowedExtension(ext string) bool {
-	for _, allowed := range s.allowedExtensions {
-		if ext == allowed {
-			return true
+// VerifyStorage checks that every file record has its upload on disk and
+// that every upload under the storage path belongs to a record
+// An upload being written when the check runs may be reported as an orphan
+func (s *FileService) VerifyStorage(ctx context.Context) (_ *domain.StorageReport, err error) {
+	ctx, end := startSpan(ctx, "FileService.VerifyStorage")
+	defer end(&err)
+
+	files, err := s.fileRepo.GetAllFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.StorageReport{
+		Files:        len(files),
+		MissingBlobs: make([]*domain.UploadedFile, 0),
+		OrphanBlobs:  make([]string, 0),
+	}
+
+	recorded := make(map[string]bool, len(files))
+	for _, file := range files {
+		path, err := filepath.Abs(file.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		recorded[path] = true
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			report.MissingBlobs = append(report.MissingBlobs, file)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	root, err := filepath.Abs(s.uploadPath)
+	if err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir // Nothing uploaded yet
+			}
+			return err
 		}
+		if d.IsDir() {
+			return nil
+		}
+		report.Blobs++
+		if !recorded[path] {
+			report.OrphanBlobs = append(report.OrphanBlobs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return false
+
+	return report, nil
 }
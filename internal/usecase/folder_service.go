@@ -1,46 +1,186 @@
 package usecase
 
 import (
+	"context"
 	"fileprintapp/internal/domain"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // FolderService handles folder-related business logic
 type FolderService struct {
 	folderRepo domain.FolderRepository
+	fileRepo   domain.FileRepository
+	uow        domain.UnitOfWork
 }
 
 // NewFolderService creates a new folder service
-func NewFolderService(folderRepo domain.FolderRepository) *FolderService {
+func NewFolderService(folderRepo domain.FolderRepository, fileRepo domain.FileRepository, uow domain.UnitOfWork) *FolderService {
 	return &FolderService{
 		folderRepo: folderRepo,
+		fileRepo:   fileRepo,
+		uow:        uow,
 	}
 }
 
 // CreateFolder creates a new folder
-func (s *FolderService) CreateFolder(name string) (*domain.Folder, error) {
+func (s *FolderService) CreateFolder(ctx context.Context, name string) (_ *domain.Folder, err error) {
+	ctx, end := startSpan(ctx, "FolderService.CreateFolder")
+	defer end(&err)
+
 	folder := &domain.Folder{
 		ID:        uuid.New().String(),
 		Name:      name,
 		CreatedAt: time.Now(),
-		FileCount: 0,
 	}
 
-	if err := s.folderRepo.CreateFolder(folder); err != nil {
+	if err := s.folderRepo.CreateFolder(ctx, folder); err != nil {
 		return nil, err
 	}
 
+	slog.InfoContext(ctx, "folder created", "folder_id", folder.ID)
 	return folder, nil
 }
 
 // GetAllFolders retrieves all folders
-func (s *FolderService) GetAllFolders() ([]*domain.Folder, error) {
-	return s.folderRepo.GetAllFolders()
+func (s *FolderService) GetAllFolders(ctx context.Context) (_ []*domain.Folder, err error) {
+	ctx, end := startSpan(ctx, "FolderService.GetAllFolders")
+	defer end(&err)
+	return s.folderRepo.GetAllFolders(ctx)
+}
+
+// ListFolders retrieves one page of folders matching the query
+func (s *FolderService) ListFolders(ctx context.Context, query domain.FolderQuery) (_ *domain.FolderPage, err error) {
+	ctx, end := startSpan(ctx, "FolderService.ListFolders")
+	defer end(&err)
+	return s.folderRepo.ListFolders(ctx, query)
 }
 
 // GetFolder retrieves a folder by ID
-func (s *FolderService) GetFolder(id string) (*domain.Folder, error) {
-	return s.folderRepo.GetFolder(id)
+func (s *FolderService) GetFolder(ctx context.Context, id string) (_ *domain.Folder, err error) {
+	ctx, end := startSpan(ctx, "FolderService.GetFolder", attribute.String("folder.id", id))
+	defer end(&err)
+	return s.folderRepo.GetFolder(ctx, id)
+}
+
+// GetFolderWithFiles retrieves a folder and its files, newest first
+func (s *FolderService) GetFolderWithFiles(ctx context.Context, id string) (_ *domain.FolderWithFiles, err error) {
+	ctx, end := startSpan(ctx, "FolderService.GetFolderWithFiles", attribute.String("folder.id", id))
+	defer end(&err)
+
+	folder, err := s.folderRepo.GetFolder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.fileRepo.GetFilesByFolder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.FolderWithFiles{Folder: folder, Files: files}, nil
+}
+
+// WithFiles attaches each folder's files, newest first
+func (s *FolderService) WithFiles(ctx context.Context, folders []*domain.Folder) (_ []*domain.FolderWithFiles, err error) {
+	ctx, end := startSpan(ctx, "FolderService.WithFiles", attribute.Int("folder.count", len(folders)))
+	defer end(&err)
+
+	ids := make([]string, len(folders))
+	for i, folder := range folders {
+		ids[i] = folder.ID
+	}
+	byFolder, err := s.fileRepo.GetFilesByFolders(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.FolderWithFiles, 0, len(folders))
+	for _, folder := range folders {
+		files := byFolder[folder.ID]
+		if files == nil {
+			files = make([]*domain.UploadedFile, 0) // Serialized as [], like GetFilesByFolder
+		}
+		result = append(result, &domain.FolderWithFiles{Folder: folder, Files: files})
+	}
+	return result, nil
+}
+
+// RenameFolder renames a folder and the folder name recorded on its files
+func (s *FolderService) RenameFolder(ctx context.Context, id, name string) (_ *domain.Folder, err error) {
+	ctx, end := startSpan(ctx, "FolderService.RenameFolder", attribute.String("folder.id", id))
+	defer end(&err)
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.NewError(domain.ErrValidation, "folder name is required")
+	}
+
+	var folder *domain.Folder
+	err = s.uow.Do(ctx, func(repos domain.Repositories) error {
+		if err := repos.Folders.RenameFolder(ctx, id, name); err != nil {
+			return err
+		}
+		if err := repos.Files.UpdateFolderName(ctx, id, name); err != nil {
+			return err
+		}
+		var err error
+		folder, err = repos.Folders.GetFolder(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "folder renamed", "folder_id", id)
+	return folder, nil
+}
+
+// DeleteFolder deletes a folder with all of its files and returns the removed folder
+// Physical files are only removed once the database change has committed
+func (s *FolderService) DeleteFolder(ctx context.Context, id string) (_ *domain.Folder, err error) {
+	ctx, end := startSpan(ctx, "FolderService.DeleteFolder", attribute.String("folder.id", id))
+	defer end(&err)
+
+	var (
+		folder *domain.Folder
+		files  []*domain.UploadedFile
+	)
+	err = s.uow.Do(ctx, func(repos domain.Repositories) error {
+		var err error
+		if folder, err = repos.Folders.GetFolder(ctx, id); err != nil {
+			return err
+		}
+		if files, err = repos.Files.GetFilesByFolder(ctx, id); err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := repos.Files.DeleteFile(ctx, file.ID); err != nil {
+				return err
+			}
+		}
+		return repos.Folders.DeleteFolder(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete physical files, then their directories once empty
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		if err := os.Remove(file.FilePath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		dirs[filepath.Dir(file.FilePath)] = true
+	}
+	for dir := range dirs {
+		os.Remove(dir) // Fails harmlessly if anything else is still in it
+	}
+
+	slog.InfoContext(ctx, "folder deleted", "folder_id", id, "files", len(files))
+	return folder, nil
 }
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"fileprintapp/internal/domain"
+)
+
+// SearchService handles search across folders and files
+type SearchService struct {
+	searchRepo domain.SearchRepository
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(searchRepo domain.SearchRepository) *SearchService {
+	return &SearchService{
+		searchRepo: searchRepo,
+	}
+}
+
+// Search returns ranked hits for a free-text query
+// An empty query (or one with only punctuation) returns no hits
+func (s *SearchService) Search(ctx context.Context, query string, limit int) ([]*domain.SearchHit, error) {
+	switch {
+	case limit <= 0:
+		limit = domain.DefaultSearchLimit
+	case limit > domain.MaxSearchLimit:
+		limit = domain.MaxSearchLimit
+	}
+	return s.searchRepo.Search(ctx, domain.SearchTerms(query), limit)
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// settingsRefreshInterval is how long the settings in effect are cached
+// Changes saved here apply at once; changes saved by another server instance
+// apply once the cache expires.
+const settingsRefreshInterval = 10 * time.Second
+
+// Settings history limits
+const (
+	DefaultSettingsHistoryLimit = 20
+	MaxSettingsHistoryLimit     = 100
+)
+
+// SettingsService handles the shop settings admins change at runtime
+type SettingsService struct {
+	settingsRepo domain.SettingsRepository
+	defaults     domain.Settings // In effect until settings are first saved
+
+	mu       sync.RWMutex
+	current  *domain.SettingsVersion
+	loadedAt time.Time
+}
+
+// NewSettingsService creates a new settings service
+// defaults (usually from the configuration) apply until an admin saves settings
+func NewSettingsService(settingsRepo domain.SettingsRepository, defaults domain.Settings) *SettingsService {
+	return &SettingsService{
+		settingsRepo: settingsRepo,
+		defaults:     defaults,
+	}
+}
+
+// Current returns the settings in effect
+// The returned version is shared and must not be modified
+func (s *SettingsService) Current(ctx context.Context) (*domain.SettingsVersion, error) {
+	s.mu.RLock()
+	current, loadedAt := s.current, s.loadedAt
+	s.mu.RUnlock()
+	if current != nil && time.Since(loadedAt) < settingsRefreshInterval {
+		return current, nil
+	}
+	return s.reload(ctx)
+}
+
+// reload reads the newest settings from the repository into the cache
+func (s *SettingsService) reload(ctx context.Context) (*domain.SettingsVersion, error) {
+	current, err := s.settingsRepo.GetSettings(ctx)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Validate normalizes in place, so work on a copy
+		defaults := s.defaults
+		defaults.AllowedExtensions = append([]string(nil), s.defaults.AllowedExtensions...)
+		current, err = &domain.SettingsVersion{Settings: defaults}, defaults.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.store(current)
+	return current, nil
+}
+
+func (s *SettingsService) store(current *domain.SettingsVersion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Never go back to an older version than one already seen
+	if s.current == nil || current.Version >= s.current.Version {
+		s.current = current
+	}
+	s.loadedAt = time.Now()
+}
+
+// Update saves new settings as the version after baseVersion and puts them
+// into effect
+// baseVersion is the version the admin edited; if someone saved a newer one
+// in the meantime the update fails with ErrConflict instead of overwriting it.
+func (s *SettingsService) Update(ctx context.Context, settings domain.Settings, baseVersion int64, changedBy string) (*domain.SettingsVersion, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := s.reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version != baseVersion {
+		return nil, domain.NewError(domain.ErrConflict, "settings were changed by someone else; reload them and try again")
+	}
+
+	next := &domain.SettingsVersion{
+		Version:   baseVersion + 1,
+		Settings:  settings,
+		ChangedBy: changedBy,
+		ChangedAt: time.Now(),
+	}
+	if err := s.settingsRepo.SaveSettings(ctx, next); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, domain.NewError(domain.ErrConflict, "settings were changed by someone else; reload them and try again")
+		}
+		return nil, err
+	}
+	s.store(next)
+	slog.InfoContext(ctx, "settings updated", "version", next.Version, "changed_by", changedBy)
+	return next, nil
+}
+
+// History returns saved settings versions, newest first
+func (s *SettingsService) History(ctx context.Context, limit int) ([]*domain.SettingsVersion, error) {
+	switch {
+	case limit <= 0:
+		limit = DefaultSettingsHistoryLimit
+	case limit > MaxSettingsHistoryLimit:
+		limit = MaxSettingsHistoryLimit
+	}
+	return s.settingsRepo.ListSettingsHistory(ctx, limit)
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates a span for every service call
+var tracer = otel.Tracer("fileprintapp/internal/usecase")
+
+// startSpan starts a span named after a service method
+// Defer the returned function with a pointer to the method's error result:
+// the error is recorded on the span, which is marked failed unless the
+// error is the caller's fault (not found, validation and so on)
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(*error)) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, func(errp *error) {
+		if err := *errp; err != nil {
+			span.RecordError(err)
+			if !isClientError(err) {
+				span.SetStatus(codes.Error, err.Error())
+			}
+		}
+		span.End()
+	}
+}
+
+// isClientError reports whether err is one the HTTP layer answers with a 4xx status
+func isClientError(err error) bool {
+	for _, kind := range []error{domain.ErrNotFound, domain.ErrValidation, domain.ErrConflict, domain.ErrTooLarge, domain.ErrUnauthorized, domain.ErrLimitExceeded} {
+		if errors.Is(err, kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fileprintapp/internal/domain"
+	"fileprintapp/internal/logging"
+)
+
+// BusMessage is a published message as carried between server instances
+// An empty topic means the message is only delivered to admin clients
+type BusMessage struct {
+	Topic     string          `json:"topic,omitempty"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	RequestID string          `json:"request_id,omitempty"` // Request that caused the event
+}
+
+// NewBusMessage marshals an event published to topic
+// The request ID in ctx, if any, is sent along with the event
+func NewBusMessage(ctx context.Context, topic string, e domain.Event) (BusMessage, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return BusMessage{}, err
+	}
+	return BusMessage{Topic: topic, Type: e.EventType(), Payload: data, RequestID: logging.RequestID(ctx)}, nil
+}
+
+// Bus relays published messages to every server instance
+// A hub with a bus does not deliver its own broadcasts directly; it publishes
+// them to the bus and delivers whatever the bus hands back, so every instance
+// (including the publisher) sees the same stream. While its listener is down
+// the hub delivers its own broadcasts locally instead.
+type Bus interface {
+	// Publish sends a message to all instances listening on the bus
+	// It must return promptly, since it is called while handling requests.
+	Publish(ctx context.Context, msg BusMessage) error
+
+	// Listen calls deliver for every message received until done is closed
+	// It calls subscribed(true) once messages published from now on will be
+	// delivered, and subscribed(false) whenever that stops being true, such
+	// as while it reconnects. An error means it stopped listening; the hub
+	// calls it again.
+	Listen(done <-chan struct{}, deliver func(BusMessage), subscribed func(bool)) error
+}
@@ -1,7 +1,9 @@
 package websocket
 
 import (
-	"log"
+	"fileprintapp/internal/domain"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,25 +17,86 @@ const (
 )
 
 // Client represents a WebSocket client
+// Admin clients receive every message; other clients only receive
+// messages published to one of their topics
 type Client struct {
-	hub  *Hub
-	conn *websocket.Conn
-	send chan []byte
+	hub     *Hub
+	conn    *websocket.Conn
+	send    chan []byte
+	admin   bool
+	topics  map[string]bool
+	version int // Negotiated event protocol version
+
+	// resume is set when the client reconnected and asked for the events
+	// published after lastSeenSeq in the given hub epoch
+	resume      bool
+	epoch       string
+	lastSeenSeq uint64
+
+	requestID string // ID of the upgrade request, for logs
 }
 
-// NewClient creates a new WebSocket client
-func NewClient(hub *Hub, conn *websocket.Conn) *Client {
+// NewClient creates a new WebSocket client subscribed to the given topics
+// speaking the given event protocol version (see NegotiateVersion)
+func NewClient(hub *Hub, conn *websocket.Conn, version int, admin bool, topics ...string) *Client {
+	subscribed := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		subscribed[topic] = true
+	}
+
 	return &Client{
-		hub:  hub,
-		conn: conn,
-		send: make(chan []byte, 256),
+		hub:     hub,
+		conn:    conn,
+		send:    make(chan []byte, 256),
+		admin:   admin,
+		topics:  subscribed,
+		version: version,
+	}
+}
+
+// NegotiateVersion picks the newest protocol version both sides support
+// Clients that don't ask for a version get the current one
+func NegotiateVersion(requested []int) (int, bool) {
+	if len(requested) == 0 {
+		return domain.EventProtocolVersion, true
+	}
+
+	best := 0
+	for _, v := range requested {
+		if v > best && slices.Contains(domain.SupportedEventProtocolVersions, v) {
+			best = v
+		}
+	}
+	return best, best != 0
+}
+
+// ResumeFrom asks the hub to replay events published after seq when the
+// client registers; epoch is the one announced in the hello message the
+// client last saw. It must be called before the client is registered.
+func (c *Client) ResumeFrom(epoch string, seq uint64) {
+	c.resume = true
+	c.epoch = epoch
+	c.lastSeenSeq = seq
+}
+
+// SetRequestID records the ID of the request that opened the connection
+// It must be called before the client is registered.
+func (c *Client) SetRequestID(id string) {
+	c.requestID = id
+}
+
+// wants reports whether a message published to topic should be delivered
+func (c *Client) wants(topic string) bool {
+	if c.admin {
+		return true
 	}
+	return topic != "" && c.topics[topic]
 }
 
 // ReadPump pumps messages from the WebSocket connection to the hub
 func (c *Client) ReadPump() {
 	defer func() {
-		c.hub.Unregister <- c
+		c.hub.Unregister(c)
 		c.conn.Close()
 	}()
 
@@ -48,7 +111,7 @@ func (c *Client) ReadPump() {
 		_, _, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("WebSocket error: %v", err)
+				slog.Warn("websocket read failed", "request_id", c.requestID, "error", err)
 			}
 			break
 		}
@@ -56,11 +119,14 @@ func (c *Client) ReadPump() {
 }
 
 // WritePump pumps messages from the hub to the WebSocket connection
+// Every message is sent in a frame of its own, since clients parse each
+// frame as a single JSON document
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
+		c.hub.pumps.Done()
 	}()
 
 	for {
@@ -72,20 +138,7 @@ func (c *Client) WritePump() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
-			}
-			w.Write(message)
-
-			// Add queued messages to the current WebSocket message
-			n := len(c.send)
-			for i := 0; i < n; i++ {
-				w.Write([]byte{'\n'})
-				w.Write(<-c.send)
-			}
-
-			if err := w.Close(); err != nil {
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
 				return
 			}
 
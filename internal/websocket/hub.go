@@ -1,76 +1,420 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fileprintapp/internal/domain"
+	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+const (
+	// historySize is the number of recent events kept for replay after a reconnect
+	historySize = 256
+
+	// broadcastBuffer is the number of published messages that can queue up
+	// while Run is busy before publishers start dropping them
+	broadcastBuffer = 1024
+
+	// Delays between attempts to restart a failed bus listener
+	minListenBackoff = time.Second
+	maxListenBackoff = time.Minute
+)
+
+// FolderTopic returns the topic name used for events about a single folder
+// Customers subscribe to it with their folder ID (pickup token)
+func FolderTopic(folderID string) string {
+	return "folder:" + folderID
+}
+
+// event is a sequenced, marshaled message kept in the replay buffer
+type event struct {
+	seq   uint64
+	topic string
+	data  []byte
+}
+
+// envelope is the wire format of every message sent to clients
+// Connection-level messages (hello) carry no sequence number; events caused
+// by an HTTP request carry its ID
+type envelope struct {
+	Seq       uint64          `json:"seq,omitempty"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+}
+
+// Stats is a snapshot of hub activity counters
+type Stats struct {
+	Clients         int    `json:"clients"`          // Currently connected clients
+	Queued          int    `json:"queued"`           // Messages waiting to be fanned out
+	Published       uint64 `json:"published"`        // Messages delivered to the hub
+	DroppedMessages uint64 `json:"dropped_messages"` // Messages dropped because the hub queue was full
+	DroppedClients  uint64 `json:"dropped_clients"`  // Clients evicted because their send buffer was full
+}
+
 // Hub maintains active WebSocket connections and broadcasts messages
+// Every broadcast gets a monotonic sequence number so clients can resume
+// from the last event they saw.
+//
+// Only Run mutates the client set; mu guards it for readers such as Stats.
+// Publishing never blocks: if Run falls behind, messages are dropped and
+// counted, and clients that cannot keep up are evicted.
+//
+// With a Bus, broadcasts go through the bus so that clients connected to
+// other server instances receive them too. Sequence numbers are assigned
+// per instance and tagged with a random epoch, so replay only works when a
+// client reconnects to the same instance; anywhere else it is told to resync.
+// If the bus listener fails it is restarted, and until then broadcasts are
+// also delivered locally.
 type Hub struct {
-	Clients    map[*Client]bool
-	Broadcast  chan []byte
-	Register   chan *Client
-	Unregister chan *Client
+	clients    map[*Client]bool
+	broadcast  chan BusMessage
+	register   chan *Client
+	unregister chan *Client
+	done       chan struct{}
+	bus        Bus
 	mu         sync.RWMutex
+
+	epoch   string  // identifies this hub's sequence, announced in every hello
+	seq     uint64  // sequence number of the last broadcast, owned by Run
+	history []event // most recent events, oldest first, owned by Run
+
+	pumps sync.WaitGroup // WritePumps of registered clients, so shutdown can wait for close frames
+
+	running         atomic.Bool
+	busListening    atomic.Bool // The bus listener is subscribed and hands this instance's broadcasts back
+	published       atomic.Uint64
+	droppedMessages atomic.Uint64
+	droppedClients  atomic.Uint64
 }
 
 // NewHub creates a new WebSocket hub
 func NewHub() *Hub {
 	return &Hub{
-		Clients:    make(map[*Client]bool),
-		Broadcast:  make(chan []byte),
-		Register:   make(chan *Client),
-		Unregister: make(chan *Client),
+		clients:    make(map[*Client]bool),
+		broadcast:  make(chan BusMessage, broadcastBuffer),
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		done:       make(chan struct{}),
+		epoch:      uuid.NewString(),
+		history:    make([]event, 0, historySize),
 	}
 }
 
-// Run starts the hub
-func (h *Hub) Run() {
+// NewHubWithBus creates a hub that relays broadcasts through bus
+func NewHubWithBus(bus Bus) *Hub {
+	h := NewHub()
+	h.bus = bus
+	return h
+}
+
+// Run starts the hub and blocks until ctx is cancelled
+// On shutdown every client's send channel is closed so its WritePump sends
+// a close frame and disconnects; WaitForClients waits for that.
+func (h *Hub) Run(ctx context.Context) {
+	h.running.Store(true)
+	defer close(h.done)
+	defer h.running.Store(false)
+
+	if h.bus != nil {
+		go h.listen(ctx)
+	}
+
 	for {
 		select {
-		case client := <-h.Register:
+		case <-ctx.Done():
 			h.mu.Lock()
-			h.Clients[client] = true
+			for client := range h.clients {
+				delete(h.clients, client)
+				close(client.send)
+			}
 			h.mu.Unlock()
-			log.Println("Client connected")
+			slog.Info("websocket hub stopped")
+			return
 
-		case client := <-h.Unregister:
+		case client := <-h.register:
+			h.pumps.Add(1) // Done when the client's WritePump returns
 			h.mu.Lock()
-			if _, ok := h.Clients[client]; ok {
-				delete(h.Clients, client)
-				close(client.send)
-				log.Println("Client disconnected")
-			}
+			h.clients[client] = true
 			h.mu.Unlock()
+			slog.Info("websocket client connected", "request_id", client.requestID, "admin", client.admin)
+			h.sendDirect(client, domain.HelloEvent{
+				Version:   client.version,
+				Supported: domain.SupportedEventProtocolVersions,
+				Epoch:     h.epoch,
+				Seq:       h.seq,
+			})
+			if client.resume {
+				h.replay(client)
+			}
+
+		case client := <-h.unregister:
+			if h.remove(client) {
+				slog.Info("websocket client disconnected", "request_id", client.requestID)
+			}
 
-		case message := <-h.Broadcast:
-			h.mu.RLock()
-			for client := range h.Clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(h.Clients, client)
-				}
+		case message := <-h.broadcast:
+			h.seq++
+			data, err := json.Marshal(envelope{Seq: h.seq, Type: message.Type, Payload: message.Payload, RequestID: message.RequestID})
+			if err != nil {
+				slog.Error("marshaling websocket message", "type", message.Type, "error", err)
+				continue
 			}
-			h.mu.RUnlock()
+			h.remember(event{seq: h.seq, topic: message.Topic, data: data})
+			h.fanOut(message.Topic, data)
+		}
+	}
+}
+
+// listen relays messages from the bus until ctx is done, restarting the
+// listener with exponential backoff whenever it stops
+// busListening follows the listener's subscription, so broadcasts are also
+// delivered locally while it starts up or reconnects.
+func (h *Hub) listen(ctx context.Context) {
+	backoff := minListenBackoff
+	for {
+		started := time.Now()
+		err := h.bus.Listen(ctx.Done(), h.enqueue, h.busListening.Store)
+		h.busListening.Store(false)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A listener that ran for a while failed afresh
+		if time.Since(started) > maxListenBackoff {
+			backoff = minListenBackoff
+		}
+		slog.Error("event bus listener stopped, delivering locally until it restarts", "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxListenBackoff)
+	}
+}
+
+// fanOut delivers data to every interested client, evicting slow consumers
+func (h *Hub) fanOut(topic string, data []byte) {
+	var slow []*Client
+
+	h.mu.RLock()
+	for client := range h.clients {
+		if !client.wants(topic) {
+			continue
+		}
+		select {
+		case client.send <- data:
+		default:
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range slow {
+		if h.remove(client) {
+			h.droppedClients.Add(1)
+			slog.Warn("websocket client evicted: send buffer full", "request_id", client.requestID)
+		}
+	}
+}
+
+// remove deletes client from the hub and closes its send channel
+// It reports false if the client was already removed
+func (h *Hub) remove(client *Client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[client]; !ok {
+		return false
+	}
+	delete(h.clients, client)
+	close(client.send)
+	return true
+}
+
+// remember appends an event to the replay buffer, evicting the oldest one when full
+func (h *Hub) remember(e event) {
+	if len(h.history) == historySize {
+		copy(h.history, h.history[1:])
+		h.history = h.history[:historySize-1]
+	}
+	h.history = append(h.history, e)
+}
+
+// replay sends a reconnecting client every buffered event it missed
+// If the client's sequence came from another epoch (a restarted hub or
+// another instance), or the gap is no longer covered by the buffer or doesn't
+// fit in the client's send buffer, the client is told to resync instead: a
+// partial replay would leave a gap it can't detect
+func (h *Hub) replay(client *Client) {
+	if client.epoch != h.epoch {
+		h.sendDirect(client, domain.ResyncRequiredEvent{})
+		return
+	}
+	if client.lastSeenSeq == h.seq {
+		return
+	}
+
+	if client.lastSeenSeq > h.seq || len(h.history) == 0 || h.history[0].seq > client.lastSeenSeq+1 {
+		h.sendDirect(client, domain.ResyncRequiredEvent{})
+		return
+	}
+
+	var missed [][]byte
+	for _, e := range h.history {
+		if e.seq > client.lastSeenSeq && client.wants(e.topic) {
+			missed = append(missed, e.data)
 		}
 	}
+	// The hello message already takes a slot; WritePump may not be draining yet
+	if len(missed) > cap(client.send)-len(client.send) {
+		h.sendDirect(client, domain.ResyncRequiredEvent{})
+		return
+	}
+	for _, data := range missed {
+		client.send <- data
+	}
+}
+
+// sendDirect sends a connection-level event to a single client without
+// sequencing or buffering it
+func (h *Hub) sendDirect(client *Client, e domain.Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		slog.Error("marshaling websocket message", "type", e.EventType(), "error", err)
+		return
+	}
+	data, err := json.Marshal(envelope{Type: e.EventType(), Payload: payload})
+	if err != nil {
+		slog.Error("marshaling websocket message", "type", e.EventType(), "error", err)
+		return
+	}
+
+	select {
+	case client.send <- data:
+	default:
+	}
+}
+
+// Register adds a client to the hub
+// It returns false if the hub has stopped, in which case the caller owns the
+// connection; otherwise the caller must start the client's WritePump
+func (h *Hub) Register(client *Client) bool {
+	select {
+	case h.register <- client:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// Unregister removes a client from the hub
+// It is safe to call after the hub has stopped or the client was evicted
+func (h *Hub) Unregister(client *Client) {
+	select {
+	case h.unregister <- client:
+	case <-h.done:
+	}
+}
+
+// Done is closed once Run has returned
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+// WaitForClients blocks until every registered client's WritePump has
+// returned, having sent its close frame, or ctx is done
+// Call it once Done is closed.
+func (h *Hub) WaitForClients(ctx context.Context) error {
+	finished := make(chan struct{})
+	go func() {
+		h.pumps.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Running reports whether Run has started and not yet returned
+func (h *Hub) Running() bool {
+	return h.running.Load()
 }
 
-// BroadcastMessage broadcasts a message to all connected clients
-func (h *Hub) BroadcastMessage(messageType string, payload interface{}) {
-	message := map[string]interface{}{
-		"type":    messageType,
-		"payload": payload,
+// Stats returns a snapshot of the hub's counters
+func (h *Hub) Stats() Stats {
+	h.mu.RLock()
+	clients := len(h.clients)
+	h.mu.RUnlock()
+
+	return Stats{
+		Clients:         clients,
+		Queued:          len(h.broadcast),
+		Published:       h.published.Load(),
+		DroppedMessages: h.droppedMessages.Load(),
+		DroppedClients:  h.droppedClients.Load(),
 	}
+}
 
-	data, err := json.Marshal(message)
+// BroadcastMessage broadcasts an event to all connected admin clients
+// The request ID in ctx, if any, is sent along with the event
+func (h *Hub) BroadcastMessage(ctx context.Context, e domain.Event) {
+	h.publish(ctx, "", e)
+}
+
+// BroadcastToTopic sends an event to clients subscribed to topic
+// Admin clients receive every event regardless of topic
+func (h *Hub) BroadcastToTopic(ctx context.Context, topic string, e domain.Event) {
+	h.publish(ctx, topic, e)
+}
+
+// publish marshals an event and hands it to the bus, or straight to the
+// local queue when there is no bus (or the bus rejected it)
+// While the bus listener is down the event goes to both, so this instance's
+// clients still get it.
+func (h *Hub) publish(ctx context.Context, topic string, e domain.Event) {
+	msg, err := NewBusMessage(ctx, topic, e)
 	if err != nil {
-		log.Printf("Error marshaling message: %v", err)
+		slog.ErrorContext(ctx, "marshaling websocket message", "type", e.EventType(), "error", err)
 		return
 	}
 
-	h.Broadcast <- data
+	if h.bus != nil {
+		err := h.bus.Publish(ctx, msg)
+		if err == nil && h.busListening.Load() {
+			return
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "event bus publish failed, delivering locally only", "type", msg.Type, "error", err)
+		}
+	}
+
+	h.enqueue(msg)
+}
+
+// enqueue queues a message for local delivery without blocking the caller
+func (h *Hub) enqueue(msg BusMessage) {
+	select {
+	case <-h.done:
+		h.droppedMessages.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case h.broadcast <- msg:
+		h.published.Add(1)
+	default:
+		h.droppedMessages.Add(1)
+		slog.Warn("websocket hub queue full, dropping message", "type", msg.Type, "request_id", msg.RequestID)
+	}
 }
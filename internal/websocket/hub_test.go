@@ -0,0 +1,345 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fileprintapp/internal/domain"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startHub runs a hub until the test ends
+func startHub(t *testing.T) *Hub {
+	t.Helper()
+	hub := NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-hub.Done()
+	})
+	return hub
+}
+
+// drain discards everything sent to client until its send channel is closed
+func drain(client *Client) {
+	for range client.send {
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestHubConcurrentUse registers, unregisters and broadcasts from many
+// goroutines at once; run it with -race
+func TestHubConcurrentUse(t *testing.T) {
+	hub := startHub(t)
+	ctx := context.Background()
+
+	const workers = 16
+	const rounds = 50
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			topic := FolderTopic(fmt.Sprintf("folder-%d", w%4))
+			for i := range rounds {
+				client := NewClient(hub, nil, domain.EventProtocolVersion, w%2 == 0, topic)
+				if i%5 == 0 {
+					client.ResumeFrom(hub.epoch, uint64(i))
+				}
+				if !hub.Register(client) {
+					t.Error("hub stopped while registering")
+					return
+				}
+				go drain(client)
+
+				hub.BroadcastToTopic(ctx, topic, domain.FileDeletedEvent{ID: fmt.Sprint(i), FolderID: topic})
+				hub.BroadcastMessage(ctx, domain.FolderRenamedEvent{ID: fmt.Sprint(w), Name: "renamed"})
+				_ = hub.Stats()
+
+				hub.Unregister(client)
+			}
+		}()
+	}
+	wg.Wait()
+
+	waitFor(t, "every client to unregister", func() bool { return hub.Stats().Clients == 0 })
+	// Messages are only dropped, and counted, when the hub queue is full
+	if stats := hub.Stats(); stats.Published+stats.DroppedMessages != 2*workers*rounds {
+		t.Errorf("Published + DroppedMessages = %d, want %d", stats.Published+stats.DroppedMessages, 2*workers*rounds)
+	}
+}
+
+// TestHubEvictsSlowClient checks that a client whose send buffer fills up is
+// evicted and counted, while clients not interested in the traffic stay
+func TestHubEvictsSlowClient(t *testing.T) {
+	hub := startHub(t)
+	ctx := context.Background()
+
+	slow := NewClient(hub, nil, domain.EventProtocolVersion, true)
+	bystander := NewClient(hub, nil, domain.EventProtocolVersion, false, FolderTopic("other"))
+	for _, client := range []*Client{slow, bystander} {
+		if !hub.Register(client) {
+			t.Fatal("hub stopped while registering")
+		}
+	}
+
+	// Nobody drains the send buffers; the hello message already takes a slot
+	for i := range cap(slow.send) + 1 {
+		hub.BroadcastToTopic(ctx, FolderTopic("busy"), domain.FileDeletedEvent{ID: fmt.Sprint(i), FolderID: "busy"})
+	}
+
+	waitFor(t, "the slow client to be evicted", func() bool { return hub.Stats().DroppedClients == 1 })
+	stats := hub.Stats()
+	if stats.Clients != 1 {
+		t.Errorf("Clients = %d, want 1 (the bystander)", stats.Clients)
+	}
+	if stats.DroppedMessages != 0 {
+		t.Errorf("DroppedMessages = %d, want 0", stats.DroppedMessages)
+	}
+
+	// Eviction closes the send channel, which makes WritePump send a close frame
+	drain(slow)
+}
+
+// loopbackBus hands published messages straight back to its listener
+// The first failures calls to Listen fail at once.
+type loopbackBus struct {
+	mu         sync.Mutex
+	failures   int
+	deliver    func(BusMessage) // Set while listening
+	subscribed func(bool)       // Set while listening, so tests can fake a reconnect
+	listens    atomic.Int32
+}
+
+func (b *loopbackBus) Publish(ctx context.Context, msg BusMessage) error {
+	b.mu.Lock()
+	deliver := b.deliver
+	b.mu.Unlock()
+	if deliver != nil {
+		deliver(msg)
+	}
+	return nil
+}
+
+func (b *loopbackBus) Listen(done <-chan struct{}, deliver func(BusMessage), subscribed func(bool)) error {
+	b.listens.Add(1)
+	b.mu.Lock()
+	if b.failures > 0 {
+		b.failures--
+		b.mu.Unlock()
+		return errors.New("listener failed")
+	}
+	b.deliver = deliver
+	b.subscribed = subscribed
+	b.mu.Unlock()
+	subscribed(true)
+
+	<-done
+	b.mu.Lock()
+	b.deliver = nil
+	b.subscribed = nil
+	b.mu.Unlock()
+	return nil
+}
+
+// setSubscribed reports a change of subscription to the running listener
+// While unsubscribed the bus drops what is published, as a reconnecting
+// Postgres listener would.
+func (b *loopbackBus) setSubscribed(t *testing.T, subscribed bool, deliver func(BusMessage)) {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribed == nil {
+		t.Fatal("the bus isn't listening")
+	}
+	b.deliver = deliver
+	b.subscribed(subscribed)
+}
+
+// nextMessage returns the next message sent to client
+func nextMessage(t *testing.T, client *Client) envelope {
+	t.Helper()
+	select {
+	case data := <-client.send:
+		var msg envelope
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("invalid message %s: %v", data, err)
+		}
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a message")
+		return envelope{}
+	}
+}
+
+// TestHubBusListenerRestart checks that broadcasts still reach local clients
+// while the bus listener is down, and that the listener is restarted
+func TestHubBusListenerRestart(t *testing.T) {
+	bus := &loopbackBus{failures: 1}
+	hub := NewHubWithBus(bus)
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-hub.Done()
+	})
+
+	client := NewClient(hub, nil, domain.EventProtocolVersion, true)
+	if !hub.Register(client) {
+		t.Fatal("hub stopped while registering")
+	}
+	if msg := nextMessage(t, client); msg.Type != domain.EventHello {
+		t.Fatalf("first message = %s, want hello", msg.Type)
+	}
+
+	waitFor(t, "the first listener to fail", func() bool { return bus.listens.Load() == 1 && !hub.busListening.Load() })
+	hub.BroadcastMessage(context.Background(), domain.FolderDeletedEvent{ID: "while-down"})
+	if msg := nextMessage(t, client); msg.Type != domain.EventFolderDeleted || msg.Seq != 1 {
+		t.Fatalf("got %s #%d, want the event broadcast while the listener was down", msg.Type, msg.Seq)
+	}
+
+	// The listener is restarted after minListenBackoff
+	deadline := time.Now().Add(minListenBackoff + time.Second)
+	for bus.listens.Load() < 2 || !hub.busListening.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("bus listener wasn't restarted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	waitFor(t, "the restarted listener to subscribe", func() bool {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		return bus.deliver != nil
+	})
+
+	// Now the bus hands the event back, and it is delivered only once
+	hub.BroadcastMessage(context.Background(), domain.FolderDeletedEvent{ID: "via-bus"})
+	if msg := nextMessage(t, client); msg.Type != domain.EventFolderDeleted || msg.Seq != 2 {
+		t.Fatalf("got %s #%d, want the event relayed by the bus", msg.Type, msg.Seq)
+	}
+	select {
+	case data := <-client.send:
+		t.Errorf("unexpected extra message %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// hello connects an admin client and returns the hello message it receives
+func hello(t *testing.T, hub *Hub, client *Client) domain.HelloEvent {
+	t.Helper()
+	if !hub.Register(client) {
+		t.Fatal("hub stopped while registering")
+	}
+	msg := nextMessage(t, client)
+	if msg.Type != domain.EventHello {
+		t.Fatalf("first message = %s, want hello", msg.Type)
+	}
+	var payload domain.HelloEvent
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("invalid hello payload %s: %v", msg.Payload, err)
+	}
+	return payload
+}
+
+// TestHubResume checks that a client resuming from what its hello announced
+// gets the events it missed, even if it saw none before disconnecting, and
+// that a sequence from another epoch is never replayed
+func TestHubResume(t *testing.T) {
+	hub := startHub(t)
+	ctx := context.Background()
+
+	hub.BroadcastMessage(ctx, domain.FolderDeletedEvent{ID: "before"})
+	waitFor(t, "the first event to be sequenced", func() bool {
+		return hub.Stats().Published == 1 && hub.Stats().Queued == 0
+	})
+
+	first := NewClient(hub, nil, domain.EventProtocolVersion, true)
+	greeting := hello(t, hub, first)
+	if greeting.Epoch == "" || greeting.Seq != 1 {
+		t.Fatalf("hello = %+v, want an epoch and seq 1", greeting)
+	}
+	hub.Unregister(first)
+	drain(first)
+
+	hub.BroadcastMessage(ctx, domain.FolderDeletedEvent{ID: "missed"})
+	waitFor(t, "the missed event to be sequenced", func() bool {
+		return hub.Stats().Published == 2 && hub.Stats().Queued == 0
+	})
+
+	tests := []struct {
+		name     string
+		epoch    string
+		wantType string
+		wantSeq  uint64
+	}{
+		{"same epoch", greeting.Epoch, domain.EventFolderDeleted, 2},
+		{"restarted hub", "another-epoch", domain.EventResyncRequired, 0},
+		{"no epoch", "", domain.EventResyncRequired, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(hub, nil, domain.EventProtocolVersion, true)
+			client.ResumeFrom(tt.epoch, greeting.Seq)
+			hello(t, hub, client)
+			if msg := nextMessage(t, client); msg.Type != tt.wantType || msg.Seq != tt.wantSeq {
+				t.Errorf("got %s #%d, want %s #%d", msg.Type, msg.Seq, tt.wantType, tt.wantSeq)
+			}
+			hub.Unregister(client)
+			drain(client)
+		})
+	}
+}
+
+// TestHubBusReconnect checks that broadcasts are delivered locally while the
+// bus listener reconnects, and go through the bus again once it has
+func TestHubBusReconnect(t *testing.T) {
+	bus := &loopbackBus{}
+	hub := NewHubWithBus(bus)
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-hub.Done()
+	})
+
+	client := NewClient(hub, nil, domain.EventProtocolVersion, true)
+	hello(t, hub, client)
+	waitFor(t, "the listener to subscribe", hub.busListening.Load)
+
+	bus.setSubscribed(t, false, nil)
+	if hub.busListening.Load() {
+		t.Fatal("hub still relies on the bus while its listener is down")
+	}
+	hub.BroadcastMessage(context.Background(), domain.FolderDeletedEvent{ID: "while-reconnecting"})
+	if msg := nextMessage(t, client); msg.Type != domain.EventFolderDeleted || msg.Seq != 1 {
+		t.Fatalf("got %s #%d, want the event broadcast while reconnecting", msg.Type, msg.Seq)
+	}
+
+	bus.setSubscribed(t, true, hub.enqueue)
+	hub.BroadcastMessage(context.Background(), domain.FolderDeletedEvent{ID: "via-bus"})
+	if msg := nextMessage(t, client); msg.Type != domain.EventFolderDeleted || msg.Seq != 2 {
+		t.Fatalf("got %s #%d, want the event relayed by the bus", msg.Type, msg.Seq)
+	}
+	select {
+	case data := <-client.send:
+		t.Errorf("unexpected extra message %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// notifyChannel is the Postgres channel every instance listens on
+	notifyChannel = "ikonprintzz_events"
+
+	// maxNotifyPayload is just under Postgres' 8000 byte NOTIFY payload limit
+	maxNotifyPayload = 7900
+
+	// listenerPingInterval keeps the listener connection from idling out
+	listenerPingInterval = 90 * time.Second
+
+	// publishTimeout bounds a NOTIFY, so a stuck database doesn't hold up
+	// the request that published the event
+	publishTimeout = 2 * time.Second
+)
+
+// ErrPayloadTooLarge is returned when a message does not fit in a NOTIFY payload
+var ErrPayloadTooLarge = errors.New("event payload exceeds NOTIFY limit")
+
+// PostgresBus implements Bus with Postgres LISTEN/NOTIFY
+// Messages are published through the shared connection pool and received on
+// a dedicated listener connection that reconnects automatically.
+type PostgresBus struct {
+	db  *sql.DB
+	dsn string
+}
+
+// NewPostgresBus creates a bus publishing through db and listening with a
+// separate connection opened from dsn
+func NewPostgresBus(db *sql.DB, dsn string) *PostgresBus {
+	return &PostgresBus{
+		db:  db,
+		dsn: dsn,
+	}
+}
+
+// Publish sends msg to every listening instance
+// It gives up after publishTimeout. ctx is only used for tracing: a request
+// that finishes first doesn't cancel the NOTIFY.
+func (b *PostgresBus) Publish(ctx context.Context, msg BusMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxNotifyPayload {
+		return ErrPayloadTooLarge
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+	defer cancel()
+	_, err = b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, notifyChannel, string(data))
+	return err
+}
+
+// Listen receives notifications until done is closed
+// The listener is subscribed once LISTEN has succeeded. It stops being
+// subscribed when its connection drops, and is subscribed again when pq has
+// reconnected and re-issued LISTEN.
+func (b *PostgresBus) Listen(done <-chan struct{}, deliver func(BusMessage), subscribed func(bool)) error {
+	listener := pq.NewListener(b.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			subscribed(false)
+		case pq.ListenerEventReconnected:
+			subscribed(true)
+		}
+		if err != nil {
+			slog.Error("event bus listener error", "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(notifyChannel); err != nil {
+		return err
+	}
+	subscribed(true)
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+
+		case n := <-listener.Notify:
+			// A nil notification means the connection was re-established
+			// and LISTEN issued again; anything published while it was down
+			// has been lost
+			if n == nil {
+				subscribed(true)
+				slog.Warn("event bus reconnected, notifications may have been missed")
+				continue
+			}
+
+			var msg BusMessage
+			if err := json.Unmarshal([]byte(n.Extra), &msg); err != nil {
+				slog.Warn("event bus: invalid notification", "error", err)
+				continue
+			}
+			deliver(msg)
+
+		case <-ticker.C:
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"fileprintapp/internal/domain"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// EventSchema builds a JSON Schema (draft 2020-12) describing every message
+// the server sends for the current protocol version. It is derived from the
+// event structs in the domain package so the two cannot drift apart; the
+// checked-in copy is regenerated with `go generate ./internal/domain`.
+func EventSchema() map[string]interface{} {
+	variants := make([]interface{}, 0, len(domain.AllEvents()))
+	for _, e := range domain.AllEvents() {
+		variants = append(variants, map[string]interface{}{
+			"title":                e.EventType(),
+			"type":                 "object",
+			"additionalProperties": false,
+			"required":             []string{"type", "payload"},
+			"properties": map[string]interface{}{
+				"seq":        map[string]interface{}{"type": "integer", "minimum": 1},
+				"type":       map[string]interface{}{"const": e.EventType()},
+				"payload":    typeSchema(reflect.TypeOf(e)),
+				"request_id": map[string]interface{}{"type": "string"},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     fmt.Sprintf("/static/schema/events.v%d.json", domain.EventProtocolVersion),
+		"title":   fmt.Sprintf("IkonPrintzz WebSocket events, protocol version %d", domain.EventProtocolVersion),
+		"oneOf":   variants,
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema maps a Go type to its JSON Schema, following encoding/json rules
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = typeSchema(field.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           properties,
+			"required":             required,
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
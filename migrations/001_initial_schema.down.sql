@@ -0,0 +1,6 @@
+-- Reverts 001_initial_schema.up.sql
+-- WARNING: Drops all folders, file metadata and admin accounts
+
+DROP TABLE IF EXISTS uploaded_files;
+DROP TABLE IF EXISTS folders;
+DROP TABLE IF EXISTS admins;
@@ -50,12 +50,9 @@ CREATE TABLE IF NOT EXISTS admins (
     created_at TIMESTAMP NOT NULL DEFAULT NOW() -- When admin was created
 );
 
--- Insert default admin (password: changeme123)
--- IMPORTANT: Change this password immediately in production!
--- The hash below is for 'changeme123'
-INSERT INTO admins (username, password_hash) 
-VALUES ('admin', '$2a$10$rC5kqXGQ8VQqVK5VYVqKQeGZxhWDYJx8aYXN5KH5jHFQTYGFqKWYO')
-ON CONFLICT (username) DO NOTHING;
+-- No admin is seeded here: the application creates the admin account from
+-- ADMIN_USERNAME / ADMIN_PASSWORD on startup (AuthService.EnsureAdmin, called
+-- from cmd/ikonprintzz/serve.go)
 
 -- ============================================
 -- COMMENTS: Document table purposes
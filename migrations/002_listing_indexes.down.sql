@@ -0,0 +1,8 @@
+-- Reverts 002_listing_indexes.up.sql
+
+DROP INDEX IF EXISTS idx_folders_name_id;
+DROP INDEX IF EXISTS idx_folders_created_at_id;
+DROP INDEX IF EXISTS idx_uploaded_files_folder_id_uploaded_at;
+DROP INDEX IF EXISTS idx_uploaded_files_file_size_id;
+DROP INDEX IF EXISTS idx_uploaded_files_file_name_id;
+DROP INDEX IF EXISTS idx_uploaded_files_uploaded_at_id;
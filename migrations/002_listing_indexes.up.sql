@@ -0,0 +1,9 @@
+-- Indexes backing the paginated listing API (GET /api/files, GET /api/folders)
+-- Each index ends in id so keyset pagination on (sort column, id) is an index scan
+
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_uploaded_at_id ON uploaded_files(uploaded_at, id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_file_name_id ON uploaded_files(file_name, id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_file_size_id ON uploaded_files(file_size, id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_folder_id_uploaded_at ON uploaded_files(folder_id, uploaded_at, id);
+CREATE INDEX IF NOT EXISTS idx_folders_created_at_id ON folders(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_folders_name_id ON folders(name, id);
@@ -0,0 +1,8 @@
+-- Reverts 003_search.up.sql
+-- WARNING: Drops extracted PDF text
+
+DROP INDEX IF EXISTS idx_folders_search;
+DROP INDEX IF EXISTS idx_uploaded_files_search;
+ALTER TABLE folders DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE uploaded_files DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE uploaded_files DROP COLUMN IF EXISTS content_text;
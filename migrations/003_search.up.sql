@@ -0,0 +1,23 @@
+-- Full-text search over folder names, file names and extracted PDF text
+-- Requires PostgreSQL 12+ (generated columns)
+--
+-- The 'simple' configuration is used because names and CVs mix languages;
+-- punctuation in file names ("John_CV.pdf") is turned into spaces so each
+-- part is indexed as its own word
+
+ALTER TABLE uploaded_files ADD COLUMN IF NOT EXISTS content_text TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE uploaded_files ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', translate(file_name, '._-', '   ')), 'A') ||
+        setweight(to_tsvector('simple', translate(folder_name, '._-', '   ')), 'B') ||
+        setweight(to_tsvector('simple', content_text), 'C')
+    ) STORED;
+
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', translate(name, '._-', '   ')), 'A')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_uploaded_files_search ON uploaded_files USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_folders_search ON folders USING GIN (search_vector);
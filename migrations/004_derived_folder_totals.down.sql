@@ -0,0 +1,10 @@
+-- Reverts 004_derived_folder_totals.up.sql
+-- Restores the cached file count from the current files
+
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS file_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE folders SET file_count = (
+    SELECT COUNT(*) FROM uploaded_files WHERE uploaded_files.folder_id = folders.id
+);
+
+ALTER TABLE uploaded_files DROP COLUMN IF EXISTS page_count;
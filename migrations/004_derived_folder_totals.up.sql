@@ -0,0 +1,6 @@
+-- Folder totals are computed from uploaded_files on every read instead of
+-- being cached in folders.file_count, which could drift under concurrent uploads
+
+ALTER TABLE uploaded_files ADD COLUMN IF NOT EXISTS page_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE folders DROP COLUMN IF EXISTS file_count;
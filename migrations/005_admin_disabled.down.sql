@@ -0,0 +1,3 @@
+-- Reverts 005_admin_disabled.up.sql
+
+ALTER TABLE admins DROP COLUMN IF EXISTS disabled;
@@ -0,0 +1,4 @@
+-- Admins can be disabled from the command line instead of being deleted
+-- Disabled admins can't log in and their existing tokens are rejected
+
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE;
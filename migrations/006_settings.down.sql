@@ -0,0 +1,3 @@
+-- Reverts 006_settings.up.sql
+
+DROP TABLE IF EXISTS settings;
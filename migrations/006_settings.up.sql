@@ -0,0 +1,10 @@
+-- Shop settings (name, upload limits, price list, opening hours) editable
+-- from the admin API. Every saved version is kept: the highest version is in
+-- effect and the others are the change history.
+
+CREATE TABLE IF NOT EXISTS settings (
+    version BIGINT PRIMARY KEY,                       -- 1, 2, 3, ... in order of saving
+    settings JSONB NOT NULL,                          -- domain.Settings as JSON
+    changed_by TEXT NOT NULL,                         -- Admin who saved this version
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,14 @@
+// Package migrations embeds the numbered SQL schema migrations
+//
+// Files are named NNN_description.up.sql with an optional matching
+// NNN_description.down.sql, and are applied in version order by
+// database.Migrate. Never edit a migration that has been applied anywhere;
+// add a new one instead (applied migrations are checksummed).
+package migrations
+
+import "embed"
+
+// FS holds every migration file
+//
+//go:embed *.sql
+var FS embed.FS
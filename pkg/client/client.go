@@ -0,0 +1,409 @@
+// Package client is a typed Go client for the File Print Service HTTP API
+// The API is described by the OpenAPI document served at /api/openapi.json.
+// Admin methods need a token, set with WithToken or obtained with Login.
+// Error responses are returned as *Problem.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the API of one server
+// It is safe for concurrent use once configured; Login updates the token
+// and should not race with other calls
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithToken authenticates admin requests with an existing token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// New creates a client for the server at baseURL (e.g., "http://localhost:8080")
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Token returns the token sent with admin requests
+func (c *Client) Token() string {
+	return c.token
+}
+
+// Login exchanges admin credentials for a token and keeps it for later calls
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	body := map[string]string{"username": username, "password": password}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/api/admin/login", nil, body, &resp); err != nil {
+		return "", err
+	}
+	c.token = resp.Token
+	return resp.Token, nil
+}
+
+// CreateFolder creates a folder
+func (c *Client) CreateFolder(ctx context.Context, name string) (*Folder, error) {
+	var folder Folder
+	if err := c.doJSON(ctx, http.MethodPost, "/api/folders", nil, map[string]string{"name": name}, &folder); err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// UploadFile uploads the contents of r as fileName into a folder
+// The file is streamed to the server rather than buffered, and r is no longer
+// read once UploadFile returns.
+func (c *Client) UploadFile(ctx context.Context, folderID, folderName, fileName string, r io.Reader) (*File, error) {
+	body, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+
+	// The server needs the folder fields before the file
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		pw.CloseWithError(writeUploadForm(form, folderID, folderName, fileName, r))
+	}()
+	defer func() {
+		body.Close() // Unblocks the writer if the request ended early
+		<-written
+	}()
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/api/upload", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var file File
+	if err := c.do(req, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// writeUploadForm writes the multipart upload form, fields first
+func writeUploadForm(form *multipart.Writer, folderID, folderName, fileName string, r io.Reader) error {
+	if err := form.WriteField("folder_id", folderID); err != nil {
+		return err
+	}
+	if err := form.WriteField("folder_name", folderName); err != nil {
+		return err
+	}
+	part, err := form.CreateFormFile("file", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return err
+	}
+	return form.Close()
+}
+
+// ListFiles returns one page of files
+func (c *Client) ListFiles(ctx context.Context, params ListFilesParams) (*FilePage, error) {
+	query := url.Values{}
+	setString(query, "folder_id", params.FolderID)
+	setString(query, "type", params.Type)
+	setTime(query, "from", params.From)
+	setTime(query, "to", params.To)
+	setString(query, "q", params.Query)
+	setString(query, "sort", params.Sort)
+	setString(query, "cursor", params.Cursor)
+	setInt(query, "limit", params.Limit)
+
+	var page FilePage
+	if err := c.doJSON(ctx, http.MethodGet, "/api/files", query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ListFolders returns one page of folders
+func (c *Client) ListFolders(ctx context.Context, params ListFoldersParams) (*FolderPage, error) {
+	query := url.Values{}
+	setTime(query, "from", params.From)
+	setTime(query, "to", params.To)
+	setString(query, "q", params.Query)
+	setString(query, "sort", params.Sort)
+	setString(query, "cursor", params.Cursor)
+	setInt(query, "limit", params.Limit)
+	if params.IncludeFiles {
+		query.Set("include", "files")
+	}
+
+	var page FolderPage
+	if err := c.doJSON(ctx, http.MethodGet, "/api/folders", query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetFolder returns a folder with its files
+func (c *Client) GetFolder(ctx context.Context, id string) (*FolderWithFiles, error) {
+	var folder FolderWithFiles
+	if err := c.doJSON(ctx, http.MethodGet, "/api/folders/"+url.PathEscape(id), nil, nil, &folder); err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// RenameFolder changes a folder's name
+func (c *Client) RenameFolder(ctx context.Context, id, name string) (*Folder, error) {
+	var folder Folder
+	if err := c.doJSON(ctx, http.MethodPatch, "/api/folders/"+url.PathEscape(id), nil, map[string]string{"name": name}, &folder); err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// DeleteFolder deletes a folder and all of its files
+func (c *Client) DeleteFolder(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/folders/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteFile deletes a file
+func (c *Client) DeleteFile(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/api/files/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ViewFile downloads a file's contents along with its content type
+// The caller must close the returned reader
+func (c *Client) ViewFile(ctx context.Context, id string) (io.ReadCloser, string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/files/"+url.PathEscape(id)+"/view", nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, "", readProblem(resp)
+	}
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// Search returns folder and file hits for q, best first
+// A limit of 0 uses the server default
+func (c *Client) Search(ctx context.Context, q string, limit int) ([]*SearchHit, error) {
+	query := url.Values{}
+	query.Set("q", q)
+	setInt(query, "limit", limit)
+
+	var resp struct {
+		Hits []*SearchHit `json:"hits"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/api/search", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Hits, nil
+}
+
+// GetSettings returns the shop settings in effect
+func (c *Client) GetSettings(ctx context.Context) (*SettingsVersion, error) {
+	var version SettingsVersion
+	if err := c.doJSON(ctx, http.MethodGet, "/api/settings", nil, nil, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// UpdateSettings replaces the settings of baseVersion (from GetSettings)
+// If someone saved a newer version in the meantime the server answers with
+// a 409 *Problem
+func (c *Client) UpdateSettings(ctx context.Context, baseVersion int64, settings Settings) (*SettingsVersion, error) {
+	body := struct {
+		Version  int64    `json:"version"`
+		Settings Settings `json:"settings"`
+	}{baseVersion, settings}
+
+	var version SettingsVersion
+	if err := c.doJSON(ctx, http.MethodPut, "/api/settings", nil, body, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// SettingsHistory returns saved settings versions, newest first
+// A limit of 0 uses the server default
+func (c *Client) SettingsHistory(ctx context.Context, limit int) ([]*SettingsVersion, error) {
+	query := url.Values{}
+	setInt(query, "limit", limit)
+
+	var resp struct {
+		Versions []*SettingsVersion `json:"versions"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/api/settings/history", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Versions, nil
+}
+
+// Diagnostics returns the server's version, uptime, readiness checks,
+// configuration (secrets redacted), storage usage and migration level
+func (c *Client) Diagnostics(ctx context.Context) (*Diagnostics, error) {
+	var diagnostics Diagnostics
+	if err := c.doJSON(ctx, http.MethodGet, "/api/admin/diagnostics", nil, nil, &diagnostics); err != nil {
+		return nil, err
+	}
+	return &diagnostics, nil
+}
+
+// WebSocketURL returns the URL of the event stream
+// With a token it receives every event; otherwise folderID selects the folder
+// to watch. A non-empty epoch asks the server to replay the events published
+// after lastSeenSeq; take both from the hello message and the events received
+// on the previous connection.
+func (c *Client) WebSocketURL(folderID, epoch string, lastSeenSeq int64) (string, error) {
+	u, err := url.Parse(c.baseURL + "/ws")
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+
+	query := url.Values{}
+	query.Set("v", "1")
+	if c.token != "" {
+		query.Set("token", c.token)
+	} else if folderID != "" {
+		query.Set("folder", folderID)
+	} else {
+		return "", errors.New("client: a token or folder ID is required")
+	}
+	if epoch != "" {
+		query.Set("epoch", epoch)
+		query.Set("last_seen_seq", strconv.FormatInt(lastSeenSeq, 10))
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// doJSON sends body (if any) as JSON and decodes the response into out (if any)
+func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := c.newRequest(ctx, method, path, query, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(req, out)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return req, nil
+}
+
+// do sends req and decodes a successful response into out
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return readProblem(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decoding %s %s response: %w", req.Method, req.URL.Path, err)
+	}
+	return nil
+}
+
+// readProblem turns an error response into a *Problem
+// Responses that aren't problem documents (e.g., from a proxy) keep their status
+func readProblem(resp *http.Response) error {
+	p := &Problem{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(p); err != nil || p.Status == 0 {
+		p = &Problem{Type: "about:blank", Status: resp.StatusCode}
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(resp.StatusCode)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		p.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return p
+}
+
+func setString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setInt(query url.Values, key string, value int) {
+	if value != 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setTime(query url.Values, key string, value time.Time) {
+	if !value.IsZero() {
+		query.Set(key, value.Format(time.RFC3339Nano))
+	}
+}
@@ -0,0 +1,297 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fileprintapp/internal/app"
+	"fileprintapp/internal/config"
+	"fileprintapp/pkg/client"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	adminUsername = "admin"
+	adminPassword = "client-test-password"
+)
+
+// newServer serves the full router on the in-memory backend and returns a
+// client for it
+// configure, if not nil, adjusts the configuration first
+func newServer(t *testing.T, configure func(cfg *config.Config)) *client.Client {
+	t.Helper()
+	cfg := &config.Config{
+		Environment:       "development",
+		AdminUsername:     adminUsername,
+		AdminPassword:     adminPassword,
+		JWTSecret:         "client-test-secret",
+		MaxFileSize:       1 << 20,
+		AllowedExtensions: []string{"pdf", "png"},
+		StorageType:       "local",
+		StoragePath:       t.TempDir(),
+		DBDriver:          "memory",
+		EventBus:          "local",
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	backend, err := app.Open(cfg)
+	if err != nil {
+		t.Fatalf("opening backend: %v", err)
+	}
+	services := app.NewServices(cfg, backend)
+	hub := app.NewHub(cfg, backend)
+	ctx, stopHub := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+
+	router := app.NewRouter(services, hub,
+		app.NewMetricsHandler(cfg, backend, hub),
+		app.NewHealthService(cfg, backend, services, hub),
+		app.NewRateLimits(cfg),
+	)
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		server.Close()
+		stopHub()
+		<-hub.Done()
+		backend.Close()
+	})
+
+	return client.New(server.URL, client.WithHTTPClient(server.Client()))
+}
+
+// minimalPDF is a one-page document with no content
+const minimalPDF = `%PDF-1.4
+1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj
+2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj
+3 0 obj << /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >> endobj
+trailer << /Root 1 0 R >>
+%%EOF
+`
+
+func TestClientRoundTrip(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+
+	if _, err := c.Login(ctx, adminUsername, adminPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if c.Token() == "" {
+		t.Fatal("Login kept no token")
+	}
+
+	folder, err := c.CreateFolder(ctx, "Thesis")
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	if folder.ID == "" || folder.Name != "Thesis" {
+		t.Fatalf("CreateFolder = %+v", folder)
+	}
+
+	file, err := c.UploadFile(ctx, folder.ID, folder.Name, "chapter1.pdf", strings.NewReader(minimalPDF))
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if file.FolderID != folder.ID || file.FileName != "chapter1.pdf" || file.FileType != "pdf" || file.FileSize != int64(len(minimalPDF)) {
+		t.Errorf("UploadFile = %+v", file)
+	}
+
+	page, err := c.ListFiles(ctx, client.ListFilesParams{FolderID: folder.ID})
+	if err != nil {
+		t.Fatalf("ListFiles: %v", err)
+	}
+	if len(page.Files) != 1 || page.Files[0].ID != file.ID {
+		t.Fatalf("ListFiles returned %d files, want only %s", len(page.Files), file.ID)
+	}
+
+	if err := c.DeleteFolder(ctx, folder.ID); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+	page, err = c.ListFiles(ctx, client.ListFilesParams{FolderID: folder.ID})
+	if err != nil {
+		t.Fatalf("ListFiles after DeleteFolder: %v", err)
+	}
+	if len(page.Files) != 0 {
+		t.Errorf("ListFiles after DeleteFolder returned %d files", len(page.Files))
+	}
+}
+
+// asProblem fails the test unless err is a *Problem with the given status
+func asProblem(t *testing.T, what string, err error, status int) *client.Problem {
+	t.Helper()
+	var problem *client.Problem
+	if !errors.As(err, &problem) {
+		t.Fatalf("%s error = %v (%T), want *client.Problem", what, err, err)
+	}
+	if problem.Status != status {
+		t.Errorf("%s status = %d, want %d", what, problem.Status, status)
+	}
+	if problem.Title == "" || problem.RequestID == "" {
+		t.Errorf("%s problem = %+v, want a title and request ID", what, problem)
+	}
+	return problem
+}
+
+func TestClientProblems(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, adminUsername, "wrong-password")
+	asProblem(t, "Login(wrong password)", err, http.StatusUnauthorized)
+
+	_, err = c.ListFiles(ctx, client.ListFilesParams{})
+	asProblem(t, "ListFiles(no token)", err, http.StatusUnauthorized)
+
+	if _, err := c.Login(ctx, adminUsername, adminPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	err = c.DeleteFolder(ctx, "missing")
+	asProblem(t, "DeleteFolder(missing)", err, http.StatusNotFound)
+
+	folder, err := c.CreateFolder(ctx, "Scans")
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	_, err = c.UploadFile(ctx, folder.ID, folder.Name, "notes.exe", strings.NewReader("MZ"))
+	if problem := asProblem(t, "UploadFile(.exe)", err, http.StatusBadRequest); problem.Detail == "" {
+		t.Error("UploadFile(.exe) problem has no detail")
+	}
+}
+
+func TestClientRateLimited(t *testing.T) {
+	c := newServer(t, func(cfg *config.Config) { cfg.RateLimitPerIP = 1 })
+	ctx := context.Background()
+
+	if _, err := c.CreateFolder(ctx, "First"); err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	_, err := c.CreateFolder(ctx, "Second")
+	problem := asProblem(t, "CreateFolder over the limit", err, http.StatusTooManyRequests)
+	if problem.RetryAfter < time.Second {
+		t.Errorf("RetryAfter = %v, want at least 1s", problem.RetryAfter)
+	}
+}
+
+func TestClientFolderQuota(t *testing.T) {
+	c := newServer(t, func(cfg *config.Config) { cfg.FolderMaxFiles = 1 })
+	ctx := context.Background()
+
+	folder, err := c.CreateFolder(ctx, "Full")
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	if _, err := c.UploadFile(ctx, folder.ID, folder.Name, "first.pdf", strings.NewReader(minimalPDF)); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+
+	// Waiting won't help, so this isn't a 429 and there is nothing to retry after
+	_, err = c.UploadFile(ctx, folder.ID, folder.Name, "second.pdf", strings.NewReader(minimalPDF))
+	if problem := asProblem(t, "UploadFile over the quota", err, http.StatusConflict); problem.RetryAfter != 0 {
+		t.Errorf("RetryAfter = %v, want none", problem.RetryAfter)
+	}
+}
+
+func TestClientUploadToMissingFolder(t *testing.T) {
+	storage := t.TempDir()
+	c := newServer(t, func(cfg *config.Config) { cfg.StoragePath = storage })
+
+	_, err := c.UploadFile(context.Background(), "missing", "Missing", "orphan.pdf", strings.NewReader(minimalPDF))
+	asProblem(t, "UploadFile(missing folder)", err, http.StatusNotFound)
+
+	// The folder is checked before anything is written
+	entries, err := os.ReadDir(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("storage holds %d entries after the refused upload, want none", len(entries))
+	}
+}
+
+func TestClientUploadLimits(t *testing.T) {
+	c := newServer(t, func(cfg *config.Config) {
+		cfg.MaxFileSize = 1 << 10
+		cfg.RateLimitPerFolder = 1
+	})
+	ctx := context.Background()
+	oversized := strings.Repeat("x", 2<<10)
+
+	busy, err := c.CreateFolder(ctx, "Busy")
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	if _, err := c.UploadFile(ctx, busy.ID, busy.Name, "first.pdf", strings.NewReader(minimalPDF)); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	// The folder's rate limit is checked before the file is read, so this
+	// isn't refused for its size
+	_, err = c.UploadFile(ctx, busy.ID, busy.Name, "second.pdf", strings.NewReader(oversized))
+	asProblem(t, "UploadFile over the folder rate limit", err, http.StatusTooManyRequests)
+
+	quiet, err := c.CreateFolder(ctx, "Quiet")
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	_, err = c.UploadFile(ctx, quiet.ID, quiet.Name, "big.pdf", strings.NewReader(oversized))
+	asProblem(t, "UploadFile over MaxFileSize", err, http.StatusRequestEntityTooLarge)
+
+	// The refused file isn't kept
+	if _, err := c.Login(ctx, adminUsername, adminPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	folder, err := c.GetFolder(ctx, quiet.ID)
+	if err != nil {
+		t.Fatalf("GetFolder: %v", err)
+	}
+	if folder.FileCount != 0 || len(folder.Files) != 0 {
+		t.Errorf("folder holds %d files after the refused upload", folder.FileCount)
+	}
+}
+
+// failingReader returns data, then err
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestClientUploadReadError(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+
+	folder, err := c.CreateFolder(ctx, "Broken")
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+
+	// The file is streamed, so a failure halfway aborts the request
+	errDisk := errors.New("disk read failed")
+	_, err = c.UploadFile(ctx, folder.ID, folder.Name, "half.pdf", &failingReader{data: minimalPDF[:40], err: errDisk})
+	if !errors.Is(err, errDisk) {
+		t.Fatalf("UploadFile error = %v, want %v", err, errDisk)
+	}
+
+	if _, err := c.Login(ctx, adminUsername, adminPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	got, err := c.GetFolder(ctx, folder.ID)
+	if err != nil {
+		t.Fatalf("GetFolder: %v", err)
+	}
+	if got.FileCount != 0 {
+		t.Errorf("folder holds %d files after the failed upload", got.FileCount)
+	}
+}
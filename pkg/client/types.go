@@ -0,0 +1,186 @@
+package client
+
+import (
+	"fmt"
+	"time"
+)
+
+// File is an uploaded file
+type File struct {
+	ID         string    `json:"id"`
+	FolderID   string    `json:"folder_id"`
+	FolderName string    `json:"folder_name"`
+	FileName   string    `json:"file_name"`
+	FileSize   int64     `json:"file_size"`
+	FileType   string    `json:"file_type"` // Lowercase extension without the dot
+	FilePath   string    `json:"file_path"`
+	UploadedAt time.Time `json:"uploaded_at"`
+	PageCount  int       `json:"page_count"` // PDF page count, 1 for images
+}
+
+// Folder is a named collection of files
+type Folder struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	FileCount  int       `json:"file_count"`
+	TotalBytes int64     `json:"total_bytes"`
+	TotalPages int       `json:"total_pages"`
+}
+
+// FolderWithFiles is a folder together with the files it contains
+type FolderWithFiles struct {
+	Folder
+	Files []*File `json:"files"`
+}
+
+// FilePage is one page of files
+type FilePage struct {
+	Files      []*File `json:"files"`
+	NextCursor string  `json:"next_cursor"` // Empty on the last page
+}
+
+// FolderPage is one page of folders
+// Files are only filled in when ListFoldersParams.IncludeFiles is set
+type FolderPage struct {
+	Folders    []*FolderWithFiles `json:"folders"`
+	NextCursor string             `json:"next_cursor"` // Empty on the last page
+}
+
+// Kinds of search hits
+const (
+	SearchKindFolder = "folder"
+	SearchKindFile   = "file"
+)
+
+// SearchHit is one ranked search result
+type SearchHit struct {
+	Kind       string  `json:"kind"` // SearchKindFolder or SearchKindFile
+	ID         string  `json:"id"`   // Folder or file ID
+	FolderID   string  `json:"folder_id"`
+	FolderName string  `json:"folder_name"`
+	FileName   string  `json:"file_name"` // Files only
+	Rank       float64 `json:"rank"`      // Higher is more relevant
+}
+
+// Settings are the shop settings admins can change at runtime
+type Settings struct {
+	ShopName          string         `json:"shop_name"`
+	MaxFileSize       int64          `json:"max_file_size"`      // Bytes
+	AllowedExtensions []string       `json:"allowed_extensions"` // Lower case, without the dot
+	Currency          string         `json:"currency"`           // ISO 4217; required with a price list
+	PriceList         []Price        `json:"price_list"`
+	OpeningHours      []OpeningHours `json:"opening_hours"`
+}
+
+// Price is one line of the price list
+type Price struct {
+	Item  string `json:"item"`
+	Cents int64  `json:"cents"` // In the currency's minor unit
+}
+
+// OpeningHours are the hours the shop is open on one day of the week
+type OpeningHours struct {
+	Day   string `json:"day"`   // monday ... sunday
+	Open  string `json:"open"`  // HH:MM
+	Close string `json:"close"` // HH:MM
+}
+
+// SettingsVersion is one saved revision of the settings
+// Version 0 is the configured defaults, in effect until settings are saved
+type SettingsVersion struct {
+	Version   int64     `json:"version"`
+	Settings  Settings  `json:"settings"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Diagnostics is the server's self-report for admins
+type Diagnostics struct {
+	Version       string            `json:"version"`
+	Revision      string            `json:"revision"`
+	GoVersion     string            `json:"go_version"`
+	StartedAt     time.Time         `json:"started_at"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	Ready         bool              `json:"ready"`
+	Checks        []HealthCheck     `json:"checks"`
+	Config        map[string]string `json:"config"` // Secrets are "[REDACTED]"
+	Storage       struct {
+		Path  string `json:"path"`
+		Files int    `json:"files"`
+		Bytes int64  `json:"bytes"`
+		Disk  *struct {
+			Total uint64 `json:"total"`
+			Free  uint64 `json:"free"`
+		} `json:"disk"` // nil where the server can't read free space
+	} `json:"storage"`
+	Migrations struct {
+		Driver   string `json:"driver"`
+		Current  int    `json:"current"`
+		Latest   int    `json:"latest"`
+		Pending  int    `json:"pending"`
+		Modified bool   `json:"modified"`
+		Note     string `json:"note"`
+	} `json:"migrations"`
+	WebSocket struct {
+		Clients         int    `json:"clients"`
+		Queued          int    `json:"queued"`
+		Published       uint64 `json:"published"`
+		DroppedMessages uint64 `json:"dropped_messages"`
+		DroppedClients  uint64 `json:"dropped_clients"`
+	} `json:"websocket"`
+}
+
+// HealthCheck is the result of one readiness check
+type HealthCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"` // "ok" or "failed"
+	Error      string `json:"error"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ListFilesParams filters and pages ListFiles
+// Zero-valued fields are left out of the request
+type ListFilesParams struct {
+	FolderID string
+	Type     string    // File extension (e.g., "pdf")
+	From     time.Time // Uploaded at or after
+	To       time.Time // Uploaded before
+	Query    string    // Case-insensitive substring of the file or folder name
+	Sort     string    // "date", "name" or "size"; prefix with "-" for descending
+	Cursor   string    // NextCursor of the previous page
+	Limit    int
+}
+
+// ListFoldersParams filters and pages ListFolders
+// Zero-valued fields are left out of the request
+type ListFoldersParams struct {
+	From         time.Time // Created at or after
+	To           time.Time // Created before
+	Query        string    // Case-insensitive substring of the folder name
+	Sort         string    // "date" or "name"; prefix with "-" for descending
+	Cursor       string    // NextCursor of the previous page
+	Limit        int
+	IncludeFiles bool // Nest each folder's files in the response
+}
+
+// Problem is an RFC 7807 error returned by the server
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance"`
+	RequestID string `json:"request_id"` // Quote it when reporting a failure
+
+	// RetryAfter is how long to wait before retrying a rate-limited (429)
+	// request, from the Retry-After header; zero when the server sent none
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return fmt.Sprintf("%d %s", p.Status, p.Title)
+	}
+	return fmt.Sprintf("%d %s: %s", p.Status, p.Title, p.Detail)
+}
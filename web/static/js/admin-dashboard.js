@@ -4,8 +4,17 @@ const foldersContainer = document.getElementById('foldersContainer');
 const totalFoldersEl = document.getElementById('totalFolders');
 const totalFilesEl = document.getElementById('totalFiles');
 const connectionStatusEl = document.getElementById('connectionStatus');
+const searchInput = document.getElementById('searchInput');
+const searchResultsEl = document.getElementById('searchResults');
+const shopNameEl = document.getElementById('shopName');
+
+// WebSocket event protocol version this dashboard understands
+// Schema: /static/schema/events.v1.json
+const PROTOCOL_VERSION = 1;
 
 let ws;
+let lastEpoch = null; // Server sequence the numbers below belong to, from hello
+let lastSeq = null; // Sequence number of the last event received
 let folders = {};
 let allFiles = [];
 
@@ -23,7 +32,13 @@ logoutBtn.addEventListener('click', () => {
 // Initialize WebSocket
 function connectWebSocket() {
     const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-    ws = new WebSocket(`${protocol}//${window.location.host}/ws`);
+    let url = `${protocol}//${window.location.host}/ws?v=${PROTOCOL_VERSION}&token=${encodeURIComponent(token)}`;
+
+    // Ask the server to replay anything we missed while disconnected
+    if (lastEpoch !== null) {
+        url += `&epoch=${encodeURIComponent(lastEpoch)}&last_seen_seq=${lastSeq}`;
+    }
+    ws = new WebSocket(url);
 
     ws.onopen = () => {
         console.log('WebSocket connected');
@@ -51,7 +66,25 @@ function connectWebSocket() {
 }
 
 function handleWebSocketMessage(message) {
+    // Connection-level messages such as hello carry no sequence number
+    if (message.seq !== undefined) {
+        lastSeq = message.seq;
+    }
+
     switch (message.type) {
+        case 'hello':
+            console.log(`WebSocket protocol version ${message.payload.version}`);
+            // A new sequence (first connection or restarted server) starts
+            // where the server stands; on a resume the replay moves lastSeq on
+            if (message.payload.epoch !== lastEpoch) {
+                lastEpoch = message.payload.epoch;
+                lastSeq = message.payload.seq;
+            }
+            break;
+        case 'resync_required':
+            // Too many events were missed to replay them; start over
+            fetchData();
+            break;
         case 'new_file':
             addFileToUI(message.payload);
             break;
@@ -61,6 +94,15 @@ function handleWebSocketMessage(message) {
         case 'folder_created':
             addFolderToUI(message.payload);
             break;
+        case 'folder_renamed':
+            renameFolderInUI(message.payload);
+            break;
+        case 'folder_deleted':
+            removeFolderFromUI(message.payload.id);
+            break;
+        case 'settings_changed':
+            applySettings(message.payload.settings);
+            break;
     }
 }
 
@@ -107,33 +149,60 @@ function addFolderToUI(folder) {
     }
 }
 
+function renameFolderInUI(folder) {
+    if (folders[folder.id]) {
+        folders[folder.id].name = folder.name;
+        folders[folder.id].files.forEach(f => f.folder_name = folder.name);
+        renderFolders();
+    }
+}
+
+function removeFolderFromUI(folderId) {
+    if (folders[folderId]) {
+        delete folders[folderId];
+        allFiles = allFiles.filter(f => f.folder_id !== folderId);
+        updateStats();
+        renderFolders();
+    }
+}
+
 // Fetch initial data
 async function fetchData() {
     try {
-        const response = await fetch('/api/files', {
-            headers: {
-                'Authorization': `Bearer ${token}`
+        // Follow next_cursor until every page has been loaded
+        const loaded = {};
+        const files = [];
+        let cursor = '';
+        do {
+            const params = new URLSearchParams({ include: 'files', limit: '200' });
+            if (cursor) {
+                params.set('cursor', cursor);
             }
-        });
 
-        if (!response.ok) {
-            throw new Error('Failed to fetch files');
-        }
+            const response = await fetch(`/api/folders?${params}`, {
+                headers: {
+                    'Authorization': `Bearer ${token}`
+                }
+            });
 
-        allFiles = await response.json() || [];
-        
-        // Organize files by folder
-        folders = {};
-        allFiles.forEach(file => {
-            if (!folders[file.folder_id]) {
-                folders[file.folder_id] = {
-                    id: file.folder_id,
-                    name: file.folder_name,
-                    files: []
-                };
+            if (!response.ok) {
+                throw new Error('Failed to fetch folders');
             }
-            folders[file.folder_id].files.push(file);
-        });
+
+            const page = await response.json();
+            (page.folders || []).forEach(folder => {
+                loaded[folder.id] = {
+                    id: folder.id,
+                    name: folder.name,
+                    files: folder.files || []
+                };
+                files.push(...loaded[folder.id].files);
+            });
+            cursor = page.next_cursor || '';
+        } while (cursor);
+
+        folders = loaded;
+        allFiles = files;
 
         updateStats();
         renderFolders();
@@ -146,6 +215,30 @@ async function fetchData() {
     }
 }
 
+async function fetchSettings() {
+    try {
+        const response = await fetch('/api/settings', {
+            headers: {
+                'Authorization': `Bearer ${token}`
+            }
+        });
+
+        if (!response.ok) {
+            throw new Error('Failed to fetch settings');
+        }
+
+        const current = await response.json();
+        applySettings(current.settings);
+    } catch (error) {
+        console.error('Error fetching settings:', error);
+    }
+}
+
+function applySettings(settings) {
+    shopNameEl.textContent = settings.shop_name;
+    document.title = `Admin Dashboard - ${settings.shop_name} 🖨️`;
+}
+
 function updateStats() {
     totalFoldersEl.textContent = Object.keys(folders).length;
     totalFilesEl.textContent = allFiles.length;
@@ -174,7 +267,11 @@ function renderFolders() {
         folderHeader.className = 'folder-header';
         folderHeader.innerHTML = `
             <h3>📁 ${folder.name}</h3>
-            <span class="folder-info">${folder.files.length} file(s)</span>
+            <span class="folder-info">${folder.files.length} file(s) • ${countPages(folder.files)} page(s)</span>
+            <div class="file-actions">
+                <button class="btn btn-print" onclick="renameFolder('${folder.id}')">✏️ Rename</button>
+                <button class="btn btn-danger" onclick="deleteFolder('${folder.id}')">🗑️ Delete folder</button>
+            </div>
         `;
         
         const filesGrid = document.createElement('div');
@@ -242,6 +339,134 @@ async function deleteFile(fileId) {
     }
 }
 
+async function renameFolder(folderId) {
+    const name = prompt('New folder name:', folders[folderId] ? folders[folderId].name : '');
+    if (!name) {
+        return;
+    }
+
+    try {
+        const response = await fetch(`/api/folders/${folderId}`, {
+            method: 'PATCH',
+            headers: {
+                'Authorization': `Bearer ${token}`,
+                'Content-Type': 'application/json'
+            },
+            body: JSON.stringify({ name })
+        });
+
+        if (!response.ok) {
+            throw new Error('Failed to rename folder');
+        }
+
+        // Folder will be updated via WebSocket message
+    } catch (error) {
+        alert(`Error: ${error.message}`);
+    }
+}
+
+async function deleteFolder(folderId) {
+    if (!confirm('Delete this folder and all of its files?')) {
+        return;
+    }
+
+    try {
+        const response = await fetch(`/api/folders/${folderId}`, {
+            method: 'DELETE',
+            headers: {
+                'Authorization': `Bearer ${token}`
+            }
+        });
+
+        if (!response.ok) {
+            throw new Error('Failed to delete folder');
+        }
+
+        // Folder will be removed via WebSocket message
+    } catch (error) {
+        alert(`Error: ${error.message}`);
+    }
+}
+
+// Search as the operator types, waiting for a short pause between keystrokes
+let searchTimer;
+let searchRequest = 0;
+
+searchInput.addEventListener('input', () => {
+    clearTimeout(searchTimer);
+    searchTimer = setTimeout(runSearch, 200);
+});
+
+async function runSearch() {
+    const query = searchInput.value.trim();
+    const request = ++searchRequest;
+
+    if (!query) {
+        searchResultsEl.innerHTML = '';
+        return;
+    }
+
+    try {
+        const response = await fetch(`/api/search?q=${encodeURIComponent(query)}`, {
+            headers: {
+                'Authorization': `Bearer ${token}`
+            }
+        });
+
+        if (!response.ok) {
+            throw new Error('Search failed');
+        }
+
+        const result = await response.json();
+
+        // Ignore responses that arrive after a newer search was started
+        if (request === searchRequest) {
+            renderSearchResults(result.hits || []);
+        }
+    } catch (error) {
+        console.error('Error searching:', error);
+    }
+}
+
+function renderSearchResults(hits) {
+    if (hits.length === 0) {
+        searchResultsEl.innerHTML = '<p class="file-meta">No matches</p>';
+        return;
+    }
+
+    // Names come from uploads, so they are set as text rather than markup
+    searchResultsEl.innerHTML = '';
+    hits.forEach(hit => {
+        const row = document.createElement('div');
+        row.className = 'search-hit';
+
+        const label = document.createElement('span');
+        if (hit.kind === 'folder') {
+            label.textContent = `📁 ${hit.folder_name}`;
+            row.appendChild(label);
+        } else {
+            label.textContent = `📄 ${hit.file_name} `;
+            const location = document.createElement('span');
+            location.className = 'file-meta';
+            location.textContent = `in ${hit.folder_name}`;
+            label.appendChild(location);
+
+            const printBtn = document.createElement('button');
+            printBtn.className = 'btn btn-print';
+            printBtn.textContent = '🖨️ Print';
+            printBtn.addEventListener('click', () => printFile(hit.id));
+
+            row.appendChild(label);
+            row.appendChild(printBtn);
+        }
+        searchResultsEl.appendChild(row);
+    });
+}
+
+function countPages(files) {
+    return files.reduce((total, file) => total + (file.page_count || 0), 0);
+}
+
 function formatFileSize(bytes) {
     if (bytes === 0) return '0 Bytes';
     const k = 1024;
@@ -253,3 +478,4 @@ function formatFileSize(bytes) {
 // Initialize
 connectWebSocket();
 fetchData();
+fetchSettings();
@@ -57,7 +57,7 @@ uploadBtn.addEventListener('click', async () => {
 
     try {
         // Create folder first
-        const folderResponse = await fetch('/api/folders', {
+        const folderResponse = await fetchWithRetry('/api/folders', {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json',
@@ -66,29 +66,31 @@ uploadBtn.addEventListener('click', async () => {
         });
 
         if (!folderResponse.ok) {
-            throw new Error('Failed to create folder');
+            throw new Error(await errorDetail(folderResponse, 'Failed to create folder'));
         }
 
         const folder = await folderResponse.json();
 
         // Upload files
         for (const file of selectedFiles) {
+            // The server reads the folder fields before the file, so they go first
             const formData = new FormData();
-            formData.append('file', file);
             formData.append('folder_id', folder.id);
             formData.append('folder_name', folder.name);
+            formData.append('file', file);
 
-            const response = await fetch('/api/upload', {
+            const response = await fetchWithRetry('/api/upload', {
                 method: 'POST',
                 body: formData
             });
 
             if (!response.ok) {
-                throw new Error(`Failed to upload ${file.name}`);
+                throw new Error(`Failed to upload ${file.name}: ${await errorDetail(response, 'upload failed')}`);
             }
         }
 
         showMessage(`Successfully uploaded ${selectedFiles.length} file(s) to folder "${folderName}"!`, 'success');
+        watchFolder(folder.id);
         
         // Reset form
         folderNameInput.value = '';
@@ -104,6 +106,53 @@ uploadBtn.addEventListener('click', async () => {
     }
 });
 
+// Sends a request, waiting and retrying when the server asks to slow down
+// (429 with Retry-After), so large batches still go through
+async function fetchWithRetry(url, options, attempts = 3) {
+    for (let attempt = 1; ; attempt++) {
+        const response = await fetch(url, options);
+        const retryAfter = Number(response.headers.get('Retry-After'));
+        if (response.status !== 429 || attempt >= attempts || !(retryAfter > 0) || retryAfter > 60) {
+            return response;
+        }
+        uploadBtn.textContent = `Waiting ${retryAfter}s...`;
+        await new Promise(resolve => setTimeout(resolve, retryAfter * 1000));
+        uploadBtn.textContent = 'Uploading...';
+    }
+}
+
+// Returns the reason the server gave for a failed request, or fallback
+async function errorDetail(response, fallback) {
+    try {
+        const problem = await response.json();
+        return problem.detail || fallback;
+    } catch {
+        return fallback;
+    }
+}
+
+// Subscribe to live status updates for the customer's own folder
+let folderSocket;
+
+function watchFolder(folderId) {
+    if (folderSocket) {
+        folderSocket.close();
+    }
+
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    folderSocket = new WebSocket(`${protocol}//${window.location.host}/ws?v=1&folder=${encodeURIComponent(folderId)}`);
+
+    folderSocket.onmessage = (event) => {
+        const message = JSON.parse(event.data);
+        if (message.type === 'file_deleted') {
+            showMessage('A file from your folder has been printed and removed', 'success');
+        } else if (message.type === 'folder_deleted') {
+            showMessage('Your folder has been printed and removed', 'success');
+            folderSocket.close();
+        }
+    };
+}
+
 function showMessage(text, type) {
     messageDiv.textContent = text;
     messageDiv.className = `message ${type}`;